@@ -0,0 +1,91 @@
+package imgvips
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var (
+	// ErrUnsupportedArgument returns when Go value can't be converted to operation argument
+	ErrUnsupportedArgument = errors.New("unsupported argument value")
+)
+
+// Args contains operation arguments by name.
+//
+// Values are converted to GValue by type:
+//   - int, int32, int64: GInt, value must be in int32 range
+//   - float32, float64: GDouble
+//   - bool: GBoolean
+//   - string: GString
+//   - []float64: GVipsArrayDouble
+//   - []int: GVipsArrayInt
+//   - []byte: GVipsBlob, data must be protected from GC and modification while operation result is used
+//   - *GValue: copy of value, so original value stays owned by caller
+//   - other Value: used as is and freed after execution
+type Args map[string]interface{}
+
+// values converts arguments to values sorted by name.
+// On error all already converted values are freed.
+func (a Args) values() ([]string, []Value, error) {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]Value, 0, len(names))
+	for _, name := range names {
+		val, err := argValue(a[name])
+		if err != nil {
+			for _, v := range values {
+				v.Free()
+			}
+
+			return nil, nil, fmt.Errorf("argument %s: %w", name, err)
+		}
+		values = append(values, val)
+	}
+
+	return names, values, nil
+}
+
+func argValue(value interface{}) (Value, error) {
+	switch v := value.(type) {
+	case int:
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, fmt.Errorf("%w: %d out of int32 range", ErrUnsupportedArgument, v)
+		}
+
+		return GInt(v), nil
+	case int32:
+		return GInt(int(v)), nil
+	case int64:
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, fmt.Errorf("%w: %d out of int32 range", ErrUnsupportedArgument, v)
+		}
+
+		return GInt(int(v)), nil
+	case float32:
+		return GDouble(float64(v)), nil
+	case float64:
+		return GDouble(v), nil
+	case bool:
+		return GBoolean(v), nil
+	case string:
+		return GString(v), nil
+	case []float64:
+		return GVipsArrayDouble(v), nil
+	case []int:
+		return GVipsArrayInt(v), nil
+	case []byte:
+		return GVipsBlob(v), nil
+	case *GValue:
+		return v.Copy()
+	case Value:
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedArgument, value)
+}
@@ -0,0 +1,187 @@
+package imgvips
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+var (
+	// ErrQueueFull returns when ExecAsync() queue has no free slots
+	ErrQueueFull = errors.New("async queue is full")
+)
+
+// asyncQueueSize is maximum number of operations waiting for execution in ExecAsync() queue
+const asyncQueueSize = 1024
+
+var asyncExecutor = newExecutor(runtime.NumCPU(), asyncQueueSize)
+
+// executor bounds number of operations executed by ExecAsync() and Graph at the same time.
+//
+// ExecAsync() tasks are executed by pool of workers from bounded queue, workers are started on first task.
+type executor struct {
+	limit   int
+	running int
+	workers int
+	tasks   chan *asyncTask
+	mu      sync.Mutex
+	cond    *sync.Cond
+}
+
+type asyncTask struct {
+	ctx    context.Context
+	op     *Operation
+	future *Future
+}
+
+func newExecutor(limit, queueSize int) *executor {
+	e := &executor{limit: limit, tasks: make(chan *asyncTask, queueSize)}
+	e.cond = sync.NewCond(&e.mu)
+
+	return e
+}
+
+func (e *executor) acquire() {
+	e.mu.Lock()
+	for e.running >= e.limit {
+		e.cond.Wait()
+	}
+	e.running++
+	e.mu.Unlock()
+}
+
+func (e *executor) release() {
+	e.mu.Lock()
+	e.running--
+	e.mu.Unlock()
+	e.cond.Signal()
+}
+
+func (e *executor) setLimit(limit int) {
+	e.mu.Lock()
+	e.limit = limit
+	if e.workers > 0 {
+		e.spawn()
+	}
+	e.mu.Unlock()
+	e.cond.Broadcast()
+}
+
+func (e *executor) getLimit() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.limit
+}
+
+func (e *executor) submit(task *asyncTask) error {
+	e.mu.Lock()
+	e.spawn()
+	e.mu.Unlock()
+
+	select {
+	case e.tasks <- task:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// spawn starts workers up to limit, must be called with e.mu held
+func (e *executor) spawn() {
+	for e.workers < e.limit {
+		e.workers++
+		go e.work()
+	}
+}
+
+func (e *executor) work() {
+	for task := range e.tasks {
+		if err := task.ctx.Err(); err != nil {
+			// Operation was canceled while waiting in queue, so it is dropped without execution
+			task.future.finish(err)
+		} else {
+			e.acquire()
+			task.future.finish(task.op.Exec())
+			e.release()
+		}
+
+		// Extra workers exit after limit decrease
+		e.mu.Lock()
+		if e.workers > e.limit {
+			e.workers--
+			e.mu.Unlock()
+
+			return
+		}
+		e.mu.Unlock()
+	}
+}
+
+// SetMaxInFlight set maximum number of operations executed by ExecAsync() and Graph at the same time.
+// Other operations wait in queue. Default is runtime.NumCPU(), values less than 1 are treated as 1.
+//
+// Synchronous Exec() calls are not limited.
+func SetMaxInFlight(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	asyncExecutor.setLimit(n)
+}
+
+// MaxInFlight return maximum number of operations executed by ExecAsync() at the same time
+func MaxInFlight() int {
+	return asyncExecutor.getLimit()
+}
+
+// Future is result of *Operation.ExecAsync()
+type Future struct {
+	done chan struct{}
+	err  error
+}
+
+func (f *Future) finish(err error) {
+	f.err = err
+	close(f.done)
+}
+
+// Done returns channel, which is closed after operation execution
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait waits for operation execution and return Exec() error.
+//
+// If operation ctx was canceled while operation was waiting in queue, Wait returns its error.
+// If ctx is done first, Wait returns ctx.Err(), but operation keeps executing or waiting in queue.
+// Output arguments can be used only after Wait returned operation result.
+func (f *Future) Wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ExecAsync puts operation to execution queue and returns immediately.
+//
+// Execution is the same as in Exec(), but operations are executed by pool of workers,
+// and number of operations executed at the same time is limited by SetMaxInFlight().
+// Queue holds up to 1024 operations, if it is full, ErrQueueFull is returned.
+// If ctx is already done, ctx.Err() is returned. If ctx is done while operation waits in queue,
+// operation is not executed and Future returns ctx.Err().
+func (op *Operation) ExecAsync(ctx context.Context) (*Future, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f := &Future{done: make(chan struct{})}
+	if err := asyncExecutor.submit(&asyncTask{ctx: ctx, op: op, future: f}); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
@@ -0,0 +1,40 @@
+package imgvips
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExecutor_QueueFull(t *testing.T) {
+	// Executor without workers keeps tasks in queue
+	e := newExecutor(0, 1)
+
+	task := &asyncTask{ctx: context.Background(), future: &Future{done: make(chan struct{})}}
+	if err := e.submit(task); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := e.submit(task); err != ErrQueueFull {
+		t.Fatalf("Expected error %v, got %v", ErrQueueFull, err)
+	}
+}
+
+func TestExecutor_CanceledInQueue(t *testing.T) {
+	e := newExecutor(0, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &Future{done: make(chan struct{})}
+	// Operation is nil, so test panics if canceled task is executed
+	if err := e.submit(&asyncTask{ctx: ctx, future: f}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	cancel()
+
+	e.mu.Lock()
+	e.limit = 1
+	e.spawn()
+	e.mu.Unlock()
+
+	if err := f.Wait(context.Background()); err != context.Canceled {
+		t.Fatalf("Expected error %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,79 @@
+package imgvips_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestOperation_ExecAsync(t *testing.T) {
+	initVips(t)
+
+	defer imgvips.SetMaxInFlight(imgvips.MaxInFlight())
+	imgvips.SetMaxInFlight(2)
+	if imgvips.MaxInFlight() != 2 {
+		t.Fatalf("Expected max in flight %d, got %d", 2, imgvips.MaxInFlight())
+	}
+
+	vals := make([]*imgvips.GValue, 0, 5)
+	futures := make([]*imgvips.Future, 0, 5)
+	for i := 0; i < 5; i++ {
+		op, err := imgvips.NewOperation("grey")
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		defer op.Free()
+
+		val := imgvips.GNullVipsImage()
+		op.AddInput("width", imgvips.GInt(10*(i+1)))
+		op.AddInput("height", imgvips.GInt(10))
+		op.AddOutput("out", val)
+
+		f, err := op.ExecAsync(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		vals = append(vals, val)
+		futures = append(futures, f)
+	}
+
+	for i, f := range futures {
+		if err := f.Wait(context.Background()); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		img, ok := vals[i].Image()
+		if !ok || img == nil {
+			t.Fatal("Expected to contain image")
+		}
+		if img.Width() != 10*(i+1) {
+			t.Errorf("Expected width %d, got %d", 10*(i+1), img.Width())
+		}
+	}
+
+	op, err := imgvips.NewOperation("resize")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer op.Free()
+
+	f, err := op.ExecAsync(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	<-f.Done()
+	if err := f.Wait(context.Background()); err == nil {
+		t.Error("Expected to return error, got nil")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := op.ExecAsync(ctx); err != context.Canceled {
+		t.Errorf("Expected error %v, got %v", context.Canceled, err)
+	}
+	if err := (&imgvips.Future{}).Wait(ctx); err != context.Canceled {
+		t.Errorf("Expected error %v, got %v", context.Canceled, err)
+	}
+}
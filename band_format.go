@@ -0,0 +1,35 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "vips/vips.h"
+*/
+import "C"
+
+// BandFormat is format of pixel band element, see VipsBandFormat
+type BandFormat int
+
+// Available band formats
+const (
+	BandFormatNotSet    BandFormat = C.VIPS_FORMAT_NOTSET
+	BandFormatUchar     BandFormat = C.VIPS_FORMAT_UCHAR
+	BandFormatChar      BandFormat = C.VIPS_FORMAT_CHAR
+	BandFormatUshort    BandFormat = C.VIPS_FORMAT_USHORT
+	BandFormatShort     BandFormat = C.VIPS_FORMAT_SHORT
+	BandFormatUint      BandFormat = C.VIPS_FORMAT_UINT
+	BandFormatInt       BandFormat = C.VIPS_FORMAT_INT
+	BandFormatFloat     BandFormat = C.VIPS_FORMAT_FLOAT
+	BandFormatComplex   BandFormat = C.VIPS_FORMAT_COMPLEX
+	BandFormatDouble    BandFormat = C.VIPS_FORMAT_DOUBLE
+	BandFormatDpComplex BandFormat = C.VIPS_FORMAT_DPCOMPLEX
+)
+
+// Size return size of one band element in bytes.
+// Return 0 for unknown format.
+func (f BandFormat) Size() int {
+	if f < BandFormatUchar || f > BandFormatDpComplex {
+		return 0
+	}
+
+	return int(C.vips_format_sizeof(C.VipsBandFormat(f)))
+}
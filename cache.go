@@ -0,0 +1,79 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "vips/vips.h"
+*/
+import "C"
+
+import (
+	"sync"
+)
+
+// cacheMaxMu guards every cache max change and read, so CacheDropAll restores actual value
+var cacheMaxMu sync.Mutex
+
+// SetCacheMax set maximum number of operation to cache at runtime
+func SetCacheMax(n int) {
+	vipsCacheSetMax(n)
+}
+
+// CacheMax return maximum number of operation to cache
+func CacheMax() int {
+	cacheMaxMu.Lock()
+	defer cacheMaxMu.Unlock()
+
+	return int(C.vips_cache_get_max())
+}
+
+// SetCacheMaxMem set maximum amount of tracked memory at runtime
+func SetCacheMaxMem(n int) {
+	vipsCacheSetMaxMem(n)
+}
+
+// CacheMaxMem return maximum amount of tracked memory
+func CacheMaxMem() int {
+	return int(C.vips_cache_get_max_mem())
+}
+
+// SetCacheMaxFiles set maximum number of tracked files at runtime
+func SetCacheMaxFiles(n int) {
+	if n < 0 {
+		n = 0
+	}
+	C.vips_cache_set_max_files(C.int(n))
+}
+
+// CacheMaxFiles return maximum number of tracked files
+func CacheMaxFiles() int {
+	return int(C.vips_cache_get_max_files())
+}
+
+// CacheSize return number of operations in cache
+func CacheSize() int {
+	return int(C.vips_cache_get_size())
+}
+
+// CacheDropAll drop all operations from cache.
+//
+// Cache is trimmed to zero size and its max is restored, so cache keeps working after call.
+func CacheDropAll() {
+	cacheMaxMu.Lock()
+	defer cacheMaxMu.Unlock()
+
+	// vips_cache_drop_all() frees cache itself and is used only on shutdown
+	max := C.vips_cache_get_max()
+	C.vips_cache_set_max(0)
+	C.vips_cache_set_max(max)
+}
+
+// SetConcurrency set number of threads to use at runtime.
+// Zero means default libvips concurrency.
+func SetConcurrency(n int) {
+	vipsConcurrencySet(n)
+}
+
+// Concurrency return number of threads to use
+func Concurrency() int {
+	return int(C.vips_concurrency_get())
+}
@@ -0,0 +1,94 @@
+package imgvips_test
+
+import (
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestSetCacheMax(t *testing.T) {
+	initVips(t)
+
+	max := imgvips.CacheMax()
+	defer imgvips.SetCacheMax(max)
+
+	imgvips.SetCacheMax(10)
+	if imgvips.CacheMax() != 10 {
+		t.Errorf("Expected cache max %d, got %d", 10, imgvips.CacheMax())
+	}
+
+	imgvips.SetCacheMax(-10)
+	if imgvips.CacheMax() != 0 {
+		t.Errorf("Expected cache max %d, got %d", 0, imgvips.CacheMax())
+	}
+}
+
+func TestSetCacheMaxMem(t *testing.T) {
+	initVips(t)
+
+	maxMem := imgvips.CacheMaxMem()
+	defer imgvips.SetCacheMaxMem(maxMem)
+
+	imgvips.SetCacheMaxMem(1024 * 1024)
+	if imgvips.CacheMaxMem() != 1024*1024 {
+		t.Errorf("Expected cache max mem %d, got %d", 1024*1024, imgvips.CacheMaxMem())
+	}
+}
+
+func TestSetCacheMaxFiles(t *testing.T) {
+	initVips(t)
+
+	maxFiles := imgvips.CacheMaxFiles()
+	defer imgvips.SetCacheMaxFiles(maxFiles)
+
+	imgvips.SetCacheMaxFiles(10)
+	if imgvips.CacheMaxFiles() != 10 {
+		t.Errorf("Expected cache max files %d, got %d", 10, imgvips.CacheMaxFiles())
+	}
+}
+
+func TestCacheDropAll(t *testing.T) {
+	initVips(t)
+
+	max := imgvips.CacheMax()
+	defer imgvips.SetCacheMax(max)
+
+	maxMem := imgvips.CacheMaxMem()
+	defer imgvips.SetCacheMaxMem(maxMem)
+
+	imgvips.SetCacheMax(100)
+	imgvips.SetCacheMaxMem(1024 * 1024 * 100)
+
+	_, op := webpLoadBytes(t)
+	op.Free()
+
+	if imgvips.CacheSize() == 0 {
+		t.Error("Expected cached operations")
+	}
+
+	imgvips.CacheDropAll()
+	if imgvips.CacheSize() != 0 {
+		t.Errorf("Expected empty cache, got %d operations", imgvips.CacheSize())
+	}
+	if imgvips.CacheMax() != 100 {
+		t.Errorf("Expected cache max %d, got %d", 100, imgvips.CacheMax())
+	}
+
+	// Cache must keep working after drop
+	_, op = webpLoadBytes(t)
+	op.Free()
+
+	if imgvips.CacheSize() == 0 {
+		t.Error("Expected cached operations after drop")
+	}
+}
+
+func TestSetConcurrency(t *testing.T) {
+	initVips(t)
+	defer imgvips.SetConcurrency(0)
+
+	imgvips.SetConcurrency(2)
+	if imgvips.Concurrency() != 2 {
+		t.Errorf("Expected concurrency %d, got %d", 2, imgvips.Concurrency())
+	}
+}
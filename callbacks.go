@@ -0,0 +1,68 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "vips/vips.h"
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Functions in this file are called from libvips, so they must not contain any C definitions in preamble.
+
+//export imgvipsTargetWrite
+func imgvipsTargetWrite(target, data unsafe.Pointer, length C.gint64) C.gint64 {
+	tw := targetWriters.get(target)
+	if tw == nil {
+		return -1
+	}
+	if length <= 0 {
+		return 0
+	}
+
+	return C.gint64(tw.write(cBytes(data, int(length))))
+}
+
+//export imgvipsSourceRead
+func imgvipsSourceRead(source, buffer unsafe.Pointer, length C.gint64) C.gint64 {
+	sr := sourceReaders.get(source)
+	if sr == nil {
+		return -1
+	}
+	if length <= 0 {
+		return 0
+	}
+
+	return C.gint64(sr.read(cBytes(buffer, int(length))))
+}
+
+//export imgvipsSourceSeek
+func imgvipsSourceSeek(source unsafe.Pointer, offset C.gint64, whence C.int) C.gint64 {
+	sr := sourceReaders.get(source)
+	if sr == nil {
+		return -1
+	}
+
+	return C.gint64(sr.seek(int64(offset), int(whence)))
+}
+
+//export imgvipsSourceFinalize
+func imgvipsSourceFinalize(source unsafe.Pointer) {
+	sourceReaders.remove(source)
+}
+
+//export imgvipsLeakObject
+func imgvipsLeakObject(typeName, nickname *C.char) {
+	leakCollector.add(LeakedObject{
+		Type:     C.GoString(typeName),
+		Nickname: C.GoString(nickname),
+	})
+}
+
+//export imgvipsLog
+func imgvipsLog(domain *C.char, level C.int, message *C.char) {
+	logHandler.log(C.GoString(domain), Level(level)&(LevelError|LevelCritical|LevelWarning|LevelMessage|LevelInfo|LevelDebug),
+		C.GoString(message))
+}
@@ -0,0 +1,242 @@
+package imgvips
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrNoLayers returns when Composite called without layers
+	ErrNoLayers = errors.New("no layers to composite")
+)
+
+// BlendMode is how layer is blended with image below it, see VipsBlendMode.
+// Values are set without C constants, because enum was added in libvips 8.6,
+// and shifted by one, so zero value is BlendModeDefault.
+type BlendMode int
+
+// Available blend modes
+const (
+	// BlendModeDefault is BlendModeOver
+	BlendModeDefault BlendMode = iota
+	BlendModeClear
+	BlendModeSource
+	BlendModeOver
+	BlendModeIn
+	BlendModeOut
+	BlendModeAtop
+	BlendModeDest
+	BlendModeDestOver
+	BlendModeDestIn
+	BlendModeDestOut
+	BlendModeDestAtop
+	BlendModeXOR
+	BlendModeAdd
+	BlendModeSaturate
+	BlendModeMultiply
+	BlendModeScreen
+	BlendModeOverlay
+	BlendModeDarken
+	BlendModeLighten
+	BlendModeColourDodge
+	BlendModeColourBurn
+	BlendModeHardLight
+	BlendModeSoftLight
+	BlendModeDifference
+	BlendModeExclusion
+)
+
+// value return VipsBlendMode value
+func (m BlendMode) value() int {
+	if m == BlendModeDefault {
+		m = BlendModeOver
+	}
+
+	return int(m) - 1
+}
+
+// Layer is image placed over base image by Composite()
+type Layer struct {
+	Image *Image
+	// Mode is blend mode, zero value is BlendModeDefault, which is BlendModeOver
+	Mode BlendMode
+	// X and Y are position of layer relative to base image top left corner
+	X int
+	Y int
+}
+
+// CompositeOptions are optional arguments of Composite()
+type CompositeOptions struct {
+	// Premultiplied means images are already premultiplied by alpha, so composite don't premultiply them
+	Premultiplied bool
+}
+
+// Composite blends layers over base image in order. If opts is nil, libvips defaults are used.
+//
+// Layers are converted to colourspace of base image and get opaque alpha band, if they have no alpha.
+// If any image is colour, greyscale images are converted to sRGB.
+// By default images are expected not premultiplied, as libvips loaders return them,
+// and composite premultiplies them itself.
+// If libvips composite has no x and y arguments (before 8.8), layers are embedded to base image size.
+//
+// Requires libvips 8.6+, otherwise ErrNotSupported will be returned.
+// Returned value is owned by caller, call *GValue.Free() after image is no longer needed.
+func Composite(base *Image, layers []Layer, opts *CompositeOptions) (*GValue, error) {
+	if len(layers) == 0 {
+		return nil, ErrNoLayers
+	}
+	if !HasOperation("composite") {
+		return nil, ErrNotSupported
+	}
+	if base.val.wasFreed() {
+		return nil, ErrImageAlreadyFreed
+	}
+
+	space := base.Interpretation()
+	if isGreyInterpretation(space) {
+		for _, layer := range layers {
+			if !isGreyInterpretation(layer.Image.Interpretation()) {
+				space = InterpretationSRGB
+				break
+			}
+		}
+	}
+
+	// aligned holds owned values of converted images
+	var aligned []*GValue
+	defer func() {
+		for _, val := range aligned {
+			val.Free()
+		}
+	}()
+
+	baseImg, err := alignColourspace(base, space, &aligned)
+	if err != nil {
+		return nil, err
+	}
+
+	withOffsets := HasArgument("composite", "x")
+	images := []*Image{baseImg}
+	modes := make([]int, 0, len(layers))
+	xs := make([]int, 0, len(layers))
+	ys := make([]int, 0, len(layers))
+
+	for _, layer := range layers {
+		img, err := alignLayer(layer, space, &aligned)
+		if err != nil {
+			return nil, err
+		}
+
+		if !withOffsets && (layer.X != 0 || layer.Y != 0) {
+			embedded, err := img.Embed(layer.X, layer.Y, baseImg.Width(), baseImg.Height(), ExtendBlack, nil)
+			if img, err = keep(&aligned, embedded, err); err != nil {
+				return nil, err
+			}
+		}
+
+		images = append(images, img)
+		modes = append(modes, layer.Mode.value())
+		xs = append(xs, layer.X)
+		ys = append(ys, layer.Y)
+	}
+
+	in, err := GVipsArrayImage(images)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := NewOperation("composite")
+	if err != nil {
+		in.Free()
+
+		return nil, err
+	}
+	defer op.Free()
+
+	op.AddInput("in", in)
+	op.AddInput("mode", GVipsArrayInt(modes))
+	if withOffsets {
+		op.AddInput("x", GVipsArrayInt(xs))
+		op.AddInput("y", GVipsArrayInt(ys))
+	}
+	if opts != nil && opts.Premultiplied {
+		op.AddInput("premultiplied", GBoolean(true))
+	}
+
+	out := GNullVipsImage()
+	op.AddOutput("out", out)
+
+	if err := op.Exec(); err != nil {
+		return nil, err
+	}
+
+	return out.ref(), nil
+}
+
+// alignLayer converts layer image to colourspace and adds opaque alpha band, if it has no alpha
+func alignLayer(layer Layer, space Interpretation, aligned *[]*GValue) (*Image, error) {
+	if layer.Image == nil || layer.Image.val.wasFreed() {
+		return nil, ErrImageAlreadyFreed
+	}
+
+	img, err := alignColourspace(layer.Image, space, aligned)
+	if err != nil || img.HasAlpha() {
+		return img, err
+	}
+
+	val, err := img.transform("bandjoin_const", Args{"c": []float64{maxAlpha(img)}})
+
+	return keep(aligned, val, err)
+}
+
+// maxAlpha return value of opaque alpha for image
+func maxAlpha(img *Image) float64 {
+	if img.Interpretation() == InterpretationScRGB {
+		return 1
+	}
+
+	switch img.Format() {
+	case BandFormatChar:
+		return math.MaxInt8
+	case BandFormatUshort:
+		return math.MaxUint16
+	case BandFormatShort:
+		return math.MaxInt16
+	case BandFormatUint:
+		return math.MaxUint32
+	case BandFormatInt:
+		return math.MaxInt32
+	case BandFormatFloat, BandFormatDouble, BandFormatComplex, BandFormatDpComplex:
+		return 1
+	default:
+		return math.MaxUint8
+	}
+}
+
+// alignColourspace converts image to colourspace, if it differs
+func alignColourspace(img *Image, space Interpretation, aligned *[]*GValue) (*Image, error) {
+	current := img.Interpretation()
+	if current == space || current == InterpretationMultiband || (isGreyInterpretation(current) && isGreyInterpretation(space)) {
+		return img, nil
+	}
+
+	val, err := img.transform("colourspace", Args{"space": int(space)})
+
+	return keep(aligned, val, err)
+}
+
+// keep adds owned transform result to aligned values, so it will be freed after composite
+func keep(aligned *[]*GValue, val *GValue, err error) (*Image, error) {
+	if err != nil {
+		return nil, err
+	}
+	*aligned = append(*aligned, val)
+
+	img, _ := val.Image()
+
+	return img, nil
+}
+
+func isGreyInterpretation(interpretation Interpretation) bool {
+	return interpretation == InterpretationBW || interpretation == InterpretationGrey16
+}
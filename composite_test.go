@@ -0,0 +1,106 @@
+package imgvips_test
+
+import (
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func memoryImage(t *testing.T, width, height, bands int, pixel ...byte) (*imgvips.GValue, *imgvips.Image) {
+	data := make([]byte, 0, width*height*bands)
+	for i := 0; i < width*height; i++ {
+		data = append(data, pixel...)
+	}
+
+	val, err := imgvips.NewImageFromMemory(data, width, height, bands, imgvips.BandFormatUchar)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	img, ok := val.Image()
+	if !ok || img == nil {
+		t.Fatal("Expected *C.VipsImage in val")
+	}
+
+	return val, img
+}
+
+func TestComposite(t *testing.T) {
+	initVips(t)
+
+	baseVal, base := memoryImage(t, 4, 4, 3, 255, 0, 0)
+	defer baseVal.Free()
+	blueVal, blue := memoryImage(t, 2, 2, 4, 0, 0, 255, 255)
+	defer blueVal.Free()
+	greyVal, grey := memoryImage(t, 1, 1, 1, 100)
+	defer greyVal.Free()
+
+	out, err := imgvips.Composite(base, []imgvips.Layer{
+		{Image: blue, Mode: imgvips.BlendModeOver, X: 1, Y: 1},
+		// Zero mode is BlendModeOver
+		{Image: grey, X: 3, Y: 0},
+	}, nil)
+	if err == imgvips.ErrNotSupported {
+		t.Skip("composite is not supported by libvips version")
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer out.Free()
+
+	img, ok := out.Image()
+	if !ok || img == nil {
+		t.Fatal("Expected *C.VipsImage in out")
+	}
+	if img.Width() != 4 || img.Height() != 4 {
+		t.Fatalf("Expected size %dx%d, got %dx%d", 4, 4, img.Width(), img.Height())
+	}
+
+	cases := []struct {
+		x, y     int
+		expected []float64
+	}{
+		{0, 0, []float64{255, 0, 0}},
+		{1, 1, []float64{0, 0, 255}},
+		{2, 2, []float64{0, 0, 255}},
+		{3, 3, []float64{255, 0, 0}},
+		{3, 0, []float64{100, 100, 100}},
+	}
+	for _, c := range cases {
+		point, err := img.GetPoint(c.x, c.y)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		for i, v := range c.expected {
+			if point[i] < v-1 || point[i] > v+1 {
+				t.Errorf("Expected pixel %v at %d,%d, got %v", c.expected, c.x, c.y, point)
+				break
+			}
+		}
+	}
+
+	premultiplied, err := imgvips.Composite(base, []imgvips.Layer{{Image: blue}}, &imgvips.CompositeOptions{Premultiplied: true})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer premultiplied.Free()
+
+	// Opaque pixels are the same for premultiplied images
+	premultipliedImg, _ := premultiplied.Image()
+	point, err := premultipliedImg.GetPoint(1, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if point[0] > 1 || point[2] < 254 {
+		t.Errorf("Expected pixel %v, got %v", []float64{0, 0, 255}, point)
+	}
+
+	if _, err := imgvips.Composite(base, nil, nil); err != imgvips.ErrNoLayers {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrNoLayers, err)
+	}
+
+	greyVal.Free()
+	if _, err := imgvips.Composite(base, []imgvips.Layer{{Image: grey, Mode: imgvips.BlendModeOver}}, nil); err != imgvips.ErrImageAlreadyFreed {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrImageAlreadyFreed, err)
+	}
+}
@@ -51,31 +51,14 @@ func main() {
 }
 
 func load() *imgvips.GValue {
-	cFilename := C.CString(inFilename)
-	defer C.free(unsafe.Pointer(cFilename))
-
-	// Find image type by inFilename. Package does not implement vips_foreign_find_load, so we call it ourselves.
-	cOpName := C.vips_foreign_find_load(cFilename)
-	if cOpName == nil {
-		log.Fatalf("don't know how to load file %s", inFilename)
-	}
-	opName := C.GoString(cOpName)
-
 	// It is better to calculate the scaling factor (or shrink) and the type of image before loading the image,
 	// so that you can use additional arguments if possible, such as shrink/scale for jpeg and webp (especially for webp).
-	op, err := imgvips.NewOperation(opName)
+	// imgvips.Thumbnail() does it for you.
+	out, op, err := imgvips.LoadFile(inFilename)
 	if err != nil {
-		log.Fatalf("operation %s not found: %v", opName, err)
-	}
-	defer op.Free()
-
-	op.AddInput("filename", imgvips.GString(inFilename))
-	out := imgvips.GNullVipsImage()
-	op.AddOutput("out", out)
-
-	if err := op.Exec(); err != nil {
 		log.Fatalf("load %s return error %v", inFilename, err)
 	}
+	defer op.Free()
 
 	// op.Free() will destroy out variable, so we make a copy
 	result, err := out.Copy()
@@ -92,30 +75,18 @@ func resize(in *imgvips.GValue) *imgvips.GValue {
 		log.Fatal("value is not image")
 	}
 
-	op, err := imgvips.NewOperation("resize")
-	if err != nil {
-		log.Fatalf("operation resize not found: %v", err)
-	}
-	defer op.Free()
-
 	scale := float64(width) / float64(image.Width())
 
-	op.AddInput("in", in)
-	op.AddInput("scale", imgvips.GDouble(scale))
-	// Set kernel. Using int kinda working
-	// Commented because CI uses vips 8.2.2, which does not have this option
-	// op.AddInput("kernel", imgvips.GInt(int(C.VIPS_KERNEL_NEAREST)))
-	out := imgvips.GNullVipsImage()
-	op.AddOutput("out", out)
-
-	if err := op.Exec(); err != nil {
-		log.Fatalf("resize image return error %v", err)
+	opts := &imgvips.ResizeOptions{}
+	// Set kernel to nearest. CI uses vips 8.2.2, which does not have kernel option, so check it first
+	if imgvips.HasArgument("resize", "kernel") {
+		opts.Kernel = imgvips.KernelNearest
 	}
 
-	// op.Free() will destroy out variable, so we make a copy
-	result, err := out.Copy()
+	// Result is owned by us, so no copy is needed
+	result, err := image.Resize(scale, opts)
 	if err != nil {
-		log.Fatalf("failed copy image %v", err)
+		log.Fatalf("resize image return error %v", err)
 	}
 
 	return result
@@ -29,7 +29,7 @@ func newGDouble() *GValue {
 		},
 	}
 
-	C.g_value_init(v.gValue, v.gType)
+	v.init()
 
 	return v
 }
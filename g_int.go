@@ -29,7 +29,7 @@ func newGInt() *GValue {
 		},
 	}
 
-	C.g_value_init(v.gValue, v.gType)
+	v.init()
 
 	return v
 }
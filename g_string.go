@@ -37,7 +37,7 @@ func newGString() *GValue {
 		},
 	}
 
-	C.g_value_init(v.gValue, v.gType)
+	v.init()
 
 	return v
 }
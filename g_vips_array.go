@@ -0,0 +1,151 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+
+static void imgvips_array_image_set(GValue *value, int i, VipsImage *image) {
+	VipsImage **images = vips_value_get_array_image(value, NULL);
+	images[i] = image;
+	g_object_ref(image);
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+func newGVipsArray(gType C.GType) *GValue {
+	var gValue C.GValue
+
+	v := &GValue{
+		gType:  gType,
+		gValue: &gValue,
+		free: func(val *GValue) {
+			if val.gValue == nil {
+				return
+			}
+			C.g_value_unset(val.gValue)
+			val.gType = C.G_TYPE_NONE
+		},
+		copy: func(val *GValue) (*GValue, error) {
+			newVal := newGVipsArray(val.gType)
+
+			C.g_value_copy(val.gValue, newVal.gValue)
+
+			return newVal, nil
+		},
+	}
+
+	v.init()
+
+	return v
+}
+
+// ArrayDouble return []float64 gValue, if type is VipsArrayDouble.
+// If type not match, ok will return false.
+// If gValue already freed, gValue will be nil, ok will be true.
+func (v *GValue) ArrayDouble() (value []float64, ok bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.gType != C.vips_array_double_get_type() {
+		return nil, false
+	}
+
+	var n C.int
+	ptr := C.vips_value_get_array_double(v.gValue, &n)
+	if ptr == nil {
+		return nil, true
+	}
+
+	value = make([]float64, int(n))
+	for i, d := range (*[1 << 28]C.double)(unsafe.Pointer(ptr))[:n:n] {
+		value[i] = float64(d)
+	}
+
+	return value, true
+}
+
+// GVipsArrayDouble transform []float64 to VipsArrayDouble gValue, e.g. for background argument
+func GVipsArrayDouble(value []float64) *GValue {
+	v := newGVipsArray(C.vips_array_double_get_type())
+
+	array := make([]C.double, len(value))
+	for i, d := range value {
+		array[i] = C.double(d)
+	}
+
+	var ptr *C.double
+	if len(array) > 0 {
+		ptr = &array[0]
+	}
+	C.vips_value_set_array_double(v.gValue, ptr, C.int(len(array)))
+
+	return v
+}
+
+// ArrayInt return []int gValue, if type is VipsArrayInt.
+// If type not match, ok will return false.
+// If gValue already freed, gValue will be nil, ok will be true.
+func (v *GValue) ArrayInt() (value []int, ok bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.gType != C.vips_array_int_get_type() {
+		return nil, false
+	}
+
+	var n C.int
+	ptr := C.vips_value_get_array_int(v.gValue, &n)
+	if ptr == nil {
+		return nil, true
+	}
+
+	value = make([]int, int(n))
+	for i, d := range (*[1 << 28]C.int)(unsafe.Pointer(ptr))[:n:n] {
+		value[i] = int(d)
+	}
+
+	return value, true
+}
+
+// GVipsArrayInt transform []int to VipsArrayInt gValue
+func GVipsArrayInt(value []int) *GValue {
+	v := newGVipsArray(C.vips_array_int_get_type())
+
+	array := make([]C.int, len(value))
+	for i, d := range value {
+		array[i] = C.int(d)
+	}
+
+	var ptr *C.int
+	if len(array) > 0 {
+		ptr = &array[0]
+	}
+	C.vips_value_set_array_int(v.gValue, ptr, C.int(len(array)))
+
+	return v
+}
+
+// GVipsArrayImage create VipsArrayImage gValue with references to images, e.g. for composite.
+// Images can be freed after call, array keeps own references.
+//
+// Return error if any image was freed.
+func GVipsArrayImage(images []*Image) (*GValue, error) {
+	for _, img := range images {
+		if img.val.wasFreed() {
+			return nil, ErrImageAlreadyFreed
+		}
+	}
+
+	v := newGVipsArray(C.vips_array_image_get_type())
+	C.vips_value_set_array_image(v.gValue, C.int(len(images)))
+	for i, img := range images {
+		C.imgvips_array_image_set(v.gValue, C.int(i), img.image)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,100 @@
+package imgvips_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestGVipsArrayDouble(t *testing.T) {
+	initVips(t)
+
+	value := []float64{255, 128.5, 0}
+	v := imgvips.GVipsArrayDouble(value)
+
+	if _, ok := v.ArrayInt(); ok {
+		t.Fatal("Expected to be not ok")
+	}
+
+	result, ok := v.ArrayDouble()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+	if !reflect.DeepEqual(result, value) {
+		t.Fatalf("Expected return %v, got %v", value, result)
+	}
+
+	v2, err := v.Copy()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	// Check multiply free
+	v.Free()
+	v.Free()
+
+	if _, ok := v.ArrayDouble(); ok {
+		t.Fatal("Expected to not be ok")
+	}
+
+	result, ok = v2.ArrayDouble()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+	if !reflect.DeepEqual(result, value) {
+		t.Fatalf("Expected copy contain %v, got %v", value, result)
+	}
+	v2.Free()
+}
+
+func TestGVipsArrayInt(t *testing.T) {
+	initVips(t)
+
+	value := []int{1, 2, 3}
+	v := imgvips.GVipsArrayInt(value)
+	defer v.Free()
+
+	if _, ok := v.ArrayDouble(); ok {
+		t.Fatal("Expected to be not ok")
+	}
+
+	result, ok := v.ArrayInt()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+	if !reflect.DeepEqual(result, value) {
+		t.Fatalf("Expected return %v, got %v", value, result)
+	}
+
+	empty := imgvips.GVipsArrayInt(nil)
+	defer empty.Free()
+
+	result, ok = empty.ArrayInt()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+	if len(result) != 0 {
+		t.Fatalf("Expected empty array, got %v", result)
+	}
+}
+
+func TestGVipsArrayImage(t *testing.T) {
+	initVips(t)
+
+	val, img := memoryImage(t, 2, 2, 1, 0)
+
+	array, err := imgvips.GVipsArrayImage([]*imgvips.Image{img, img})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	// Array keeps own references
+	val.Free()
+	array.Free()
+	array.Free()
+
+	if _, err := imgvips.GVipsArrayImage([]*imgvips.Image{img}); err != imgvips.ErrImageAlreadyFreed {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrImageAlreadyFreed, err)
+	}
+}
@@ -4,10 +4,27 @@ package imgvips
 #cgo pkg-config: vips
 #include "stdlib.h"
 #include "vips/vips.h"
+
+static int imgvips_blob_free(void *data, void *area) {
+	free(data);
+
+	return 0;
+}
+
+static VipsBlob *imgvips_blob_copy(const void *data, size_t length) {
+	void *buf = malloc(length);
+	if (buf == NULL) {
+		return NULL;
+	}
+	memcpy(buf, data, length);
+
+	return vips_blob_new(imgvips_blob_free, buf, length);
+}
 */
 import "C"
 
 import (
+	"fmt"
 	"unsafe"
 )
 
@@ -47,6 +64,55 @@ func (v *GValue) Bytes() (value []byte, ok bool) {
 	return value, true
 }
 
+// blobSize return size of VipsBlob data, 0 if type not match
+func (v *GValue) blobSize() int64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.gValue == nil || v.gType != C.vips_blob_get_type() {
+		return 0
+	}
+
+	ptr := C.g_value_peek_pointer(v.gValue)
+	if ptr == nil {
+		return 0
+	}
+
+	var gSize C.gsize
+	C.vips_blob_get((*C.VipsBlob)(ptr), &gSize)
+
+	return int64(gSize)
+}
+
+// BytesView calls fn with bytes slice pointed directly to VipsBlob data, without copy.
+//
+// Slice is valid only until fn returns, it must not be modified or retained.
+// Value is read-locked while fn runs, so fn must not call Free() on the same value.
+// If type not match or VipsBlob already freed, fn will not be called and ok will return false.
+// If VipsBlob is empty, fn will be called with nil slice, ok will be true.
+func (v *GValue) BytesView(fn func(data []byte)) (ok bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.gType != C.vips_blob_get_type() {
+		return false
+	}
+
+	ptr := C.g_value_peek_pointer(v.gValue)
+	if ptr == nil {
+		fn(nil)
+
+		return true
+	}
+
+	var gSize C.gsize
+	result := C.vips_blob_get((*C.VipsBlob)(ptr), &gSize)
+
+	fn(cBytes(unsafe.Pointer(result), int(gSize)))
+
+	return true
+}
+
 // GVipsBlob create VipsBlob from bytes array.
 //
 // You must protect bytes array from GC and modification while using the VipsImage loaded from this blob.
@@ -85,7 +151,32 @@ func GNullVipsBlob() *GValue {
 		free: freeVipsBlobAreaFn,
 	}
 
-	C.g_value_init(v.gValue, v.gType)
+	v.init()
+
+	return v
+}
+
+// GVipsBlobCopy create VipsBlob from copy of bytes array.
+//
+// Data is copied to C memory, which will be freed by libvips with VipsBlob,
+// so bytes array can be modified or collected by GC right after call.
+// Function panics if C memory can't be allocated, as Go does when make() fails.
+//
+// Calling Copy() at GValue with type VipsBlob is forbidden.
+func GVipsBlobCopy(data []byte) *GValue {
+	v := GNullVipsBlob()
+	if len(data) == 0 {
+		return v
+	}
+
+	blob := C.imgvips_blob_copy(unsafe.Pointer(&data[0]), C.size_t(len(data)))
+	if blob == nil {
+		v.Free()
+
+		panic(fmt.Sprintf("imgvips: can't allocate %d bytes for blob copy", len(data)))
+	}
+
+	C.g_value_take_boxed(v.gValue, C.gconstpointer(blob))
 
 	return v
 }
@@ -48,6 +48,74 @@ func TestGBytes(t *testing.T) {
 	}
 }
 
+func TestGVipsBlobCopy(t *testing.T) {
+	initVips(t)
+
+	data := []byte("foobar")
+	v := imgvips.GVipsBlobCopy(data)
+	defer v.Free()
+
+	// Source slice can be modified after copy
+	data[0] = 'b'
+
+	result, ok := v.Bytes()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+	if !bytes.Equal(result, []byte("foobar")) {
+		t.Fatalf("Expected return %q, got %q", "foobar", result)
+	}
+
+	empty := imgvips.GVipsBlobCopy(nil)
+	defer empty.Free()
+
+	result, ok = empty.Bytes()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+	if len(result) != 0 {
+		t.Fatalf("Expected return data with %d size, got %d size", 0, len(result))
+	}
+}
+
+func TestGValue_BytesView(t *testing.T) {
+	initVips(t)
+
+	data := []byte("foobar")
+	v := imgvips.GVipsBlobCopy(data)
+
+	var result []byte
+	ok := v.BytesView(func(view []byte) {
+		result = append(result, view...)
+	})
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+	if !bytes.Equal(result, data) {
+		t.Fatalf("Expected return %q, got %q", data, result)
+	}
+
+	v.Free()
+
+	called := false
+	ok = v.BytesView(func(view []byte) {
+		called = true
+	})
+	if ok {
+		t.Fatal("Expected to not be ok")
+	}
+	if called {
+		t.Fatal("Expected to not call fn")
+	}
+
+	i := imgvips.GInt(1)
+	defer i.Free()
+
+	if i.BytesView(func(view []byte) {}) {
+		t.Fatal("Expected to not be ok")
+	}
+}
+
 func TestGNullVipsBlob(t *testing.T) {
 	initVips(t)
 
@@ -109,3 +177,24 @@ func BenchmarkGNullVipsBlob(b *testing.B) {
 		val.Free()
 	}
 }
+
+func BenchmarkGVipsBlobCopy(b *testing.B) {
+	initVips(b)
+
+	data, err := ioutil.ReadFile("./tests/fixtures/img.webp")
+	if err != nil {
+		b.Fatalf("Unexpected error %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		val := imgvips.GVipsBlobCopy(data)
+		val.BytesView(func(result []byte) {
+			if len(data) != len(result) {
+				b.Fatalf("Expected return bytes with len %d, got with len %d", len(data), len(result))
+			}
+		})
+		val.Free()
+	}
+}
@@ -33,6 +33,35 @@ func (v *GValue) Image() (value *Image, ok bool) {
 	}, true
 }
 
+// ref create new value with the same *C.VipsImage and own references to it
+func (v *GValue) ref() *GValue {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	newVal := GNullVipsImage()
+	if v.gValue == nil || v.gType != C.vips_image_get_type() {
+		return newVal
+	}
+
+	ptr := C.g_value_peek_pointer(v.gValue)
+	if ptr == nil {
+		return newVal
+	}
+
+	// gVipsImageFree releases two references, as for operation output
+	C.g_value_set_object(newVal.gValue, C.gpointer(ptr))
+	C.g_object_ref(C.gpointer(ptr))
+
+	return newVal
+}
+
+func (v *GValue) isImage() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.gType == C.vips_image_get_type()
+}
+
 // GNullVipsImage create empty glib object gValue with type for *C.VipsImage.
 //
 // Calling Copy() at empty *C.VipsImage will return error.
@@ -46,7 +75,7 @@ func GNullVipsImage() *GValue {
 		copy:   gVipsImageCopy,
 	}
 
-	C.g_value_init(v.gValue, v.gType)
+	v.init()
 
 	return v
 }
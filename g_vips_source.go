@@ -0,0 +1,253 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+
+#if VIPS_MAJOR_VERSION > 8 || (VIPS_MAJOR_VERSION == 8 && VIPS_MINOR_VERSION >= 9)
+extern gint64 imgvipsSourceRead(void *source, void *buffer, gint64 length);
+extern gint64 imgvipsSourceSeek(void *source, gint64 offset, int whence);
+extern void imgvipsSourceFinalize(void *source);
+
+static gint64 imgvips_source_read(VipsSourceCustom *source, void *buffer, gint64 length, void *user) {
+	return imgvipsSourceRead(source, buffer, length);
+}
+
+static gint64 imgvips_source_seek(VipsSourceCustom *source, gint64 offset, int whence, void *user) {
+	return imgvipsSourceSeek(source, offset, whence);
+}
+
+static void imgvips_source_finalize(gpointer data, GObject *source) {
+	imgvipsSourceFinalize(source);
+}
+
+static GType imgvips_source_get_type(void) {
+	return vips_source_get_type();
+}
+
+static void *imgvips_source_custom_new(gboolean seekable) {
+	VipsSourceCustom *source = vips_source_custom_new();
+	g_signal_connect(source, "read", G_CALLBACK(imgvips_source_read), NULL);
+	if (seekable) {
+		g_signal_connect(source, "seek", G_CALLBACK(imgvips_source_seek), NULL);
+	}
+	g_object_weak_ref(G_OBJECT(source), imgvips_source_finalize, NULL);
+
+	return source;
+}
+
+static const char *imgvips_foreign_find_load_source(void *source) {
+	return vips_foreign_find_load_source(VIPS_SOURCE(source));
+}
+#else
+static GType imgvips_source_get_type(void) {
+	return G_TYPE_NONE;
+}
+
+static void *imgvips_source_custom_new(gboolean seekable) {
+	return NULL;
+}
+
+static const char *imgvips_foreign_find_load_source(void *source) {
+	return NULL;
+}
+#endif
+*/
+import "C"
+
+import (
+	"io"
+	"sync"
+	"unsafe"
+)
+
+// maxEmptyReads is number of reads without data and error, after which reader is failed, like in bufio
+const maxEmptyReads = 100
+
+var sourceReaders = &readersRegistry{
+	readers: make(map[unsafe.Pointer]*sourceReader),
+}
+
+type sourceReader struct {
+	r   io.Reader
+	err error
+	mu  sync.Mutex
+}
+
+type readersRegistry struct {
+	readers map[unsafe.Pointer]*sourceReader
+	mu      sync.RWMutex
+}
+
+func (r *readersRegistry) add(source unsafe.Pointer, reader io.Reader) {
+	r.mu.Lock()
+	r.readers[source] = &sourceReader{r: reader}
+	r.mu.Unlock()
+}
+
+func (r *readersRegistry) get(source unsafe.Pointer) *sourceReader {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.readers[source]
+}
+
+func (r *readersRegistry) remove(source unsafe.Pointer) {
+	r.mu.Lock()
+	delete(r.readers, source)
+	r.mu.Unlock()
+}
+
+// read passes chunk of data from io.Reader to libvips, 0 means end of data.
+// libvips can read source from worker threads, so reads are serialized.
+func (sr *sourceReader) read(buffer []byte) int {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if sr.err != nil {
+		return -1
+	}
+
+	var n int
+	var err error
+	// io.Reader can return 0 without error, but libvips treats 0 as end of data
+	for i := 0; i < maxEmptyReads && n == 0 && err == nil; i++ {
+		n, err = sr.r.Read(buffer)
+	}
+	if n == 0 && err == nil {
+		err = io.ErrNoProgress
+	}
+	if err == io.EOF {
+		return n
+	}
+	if err != nil {
+		sr.err = err
+
+		return -1
+	}
+
+	return n
+}
+
+func (sr *sourceReader) seek(offset int64, whence int) int64 {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	seeker, ok := sr.r.(io.Seeker)
+	if !ok {
+		return -1
+	}
+
+	pos, err := seeker.Seek(offset, whence)
+	if err != nil {
+		return -1
+	}
+
+	return pos
+}
+
+// GVipsSourceFromReader create VipsSourceCustom, which reads image data from r.
+//
+// VipsSource is used in *load_source operations, e.g. jpegload_source or thumbnail_source.
+// If r is io.Seeker, libvips can seek it instead of buffering data in memory.
+// Images are loaded lazily, so r must stay readable until all images loaded from source are freed.
+//
+// Requires libvips 8.9+, otherwise ErrNotSupported will be returned.
+//
+// Calling Copy() at GValue with type VipsSource is forbidden.
+func GVipsSourceFromReader(r io.Reader) (*GValue, error) {
+	_, seekable := r.(io.Seeker)
+
+	cSeekable := C.gboolean(0)
+	if seekable {
+		cSeekable = 1
+	}
+
+	source := C.imgvips_source_custom_new(cSeekable)
+	if source == nil {
+		return nil, ErrNotSupported
+	}
+
+	// Reader must be registered before libvips starts reading
+	sourceReaders.add(source, r)
+
+	v := newSourceValue(C.gpointer(source))
+	C.g_object_unref(C.gpointer(source))
+
+	return v, nil
+}
+
+// newSourceValue create GValue, which holds own reference to VipsSource
+func newSourceValue(source C.gpointer) *GValue {
+	var gValue C.GValue
+
+	v := &GValue{
+		gType:  C.imgvips_source_get_type(),
+		gValue: &gValue,
+		free: func(val *GValue) {
+			if val.gValue == nil {
+				return
+			}
+			// Reader is removed from registry, when libvips finalizes source
+			C.g_value_unset(val.gValue)
+			val.gType = C.G_TYPE_NONE
+		},
+		copy: func(val *GValue) (*GValue, error) {
+			return nil, ErrCopyForbidden
+		},
+	}
+
+	v.init()
+	C.g_value_set_object(v.gValue, source)
+
+	return v
+}
+
+// sourceRef return new value with the same VipsSource, so source can be passed to several operations
+func (v *GValue) sourceRef() *GValue {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return newSourceValue(C.g_value_peek_pointer(v.gValue))
+}
+
+// findLoadSource return name of loader for VipsSource value
+func (v *GValue) findLoadSource() (string, error) {
+	v.mu.RLock()
+	cOpName := C.imgvips_foreign_find_load_source(unsafe.Pointer(C.g_value_peek_pointer(v.gValue)))
+	v.mu.RUnlock()
+
+	if cOpName == nil {
+		VipsErrorFree()
+
+		return "", ErrUnknownFormat
+	}
+
+	return C.GoString(cOpName), nil
+}
+
+// sourceError return error returned by io.Reader, if value is VipsSource
+func (v *GValue) sourceError() error {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.gValue == nil || v.gType == C.G_TYPE_NONE || v.gType != C.imgvips_source_get_type() {
+		return nil
+	}
+
+	ptr := C.g_value_peek_pointer(v.gValue)
+	if ptr == nil {
+		return nil
+	}
+
+	sr := sourceReaders.get(unsafe.Pointer(ptr))
+	if sr == nil {
+		return nil
+	}
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	return sr.err
+}
@@ -0,0 +1,82 @@
+package imgvips_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+func sourceLoad(t *testing.T, r io.Reader) (*imgvips.GValue, *imgvips.Operation, error) {
+	source, err := imgvips.GVipsSourceFromReader(r)
+	if err == imgvips.ErrNotSupported {
+		t.Skip("VipsSource is not supported by libvips version")
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	op, err := imgvips.NewOperation("webpload_source")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	out := imgvips.GNullVipsImage()
+	op.AddInput("source", source)
+	op.AddOutput("out", out)
+
+	return out, op, op.Exec()
+}
+
+func TestGVipsSourceFromReader(t *testing.T) {
+	initVips(t)
+
+	data, err := ioutil.ReadFile("./tests/fixtures/small.webp")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	// bytes.Reader is seekable, io.MultiReader is not
+	for _, r := range []io.Reader{bytes.NewReader(data), io.MultiReader(bytes.NewReader(data))} {
+		out, op, err := sourceLoad(t, r)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		img, ok := out.Image()
+		if !ok || img == nil {
+			t.Fatal("Expected *C.VipsImage in out")
+		}
+		if img.Width() != 100 {
+			t.Errorf("Expected width %d, got %d", 100, img.Width())
+		}
+		if _, err := img.WriteToMemory(); err != nil {
+			t.Errorf("Unexpected error %v", err)
+		}
+
+		op.Free()
+	}
+}
+
+func TestGVipsSourceFromReader_Error(t *testing.T) {
+	initVips(t)
+
+	readErr := errors.New("read failed")
+	_, op, err := sourceLoad(t, failingReader{err: readErr})
+	defer op.Free()
+
+	if err != readErr {
+		t.Fatalf("Expected error %v, got %v", readErr, err)
+	}
+}
@@ -0,0 +1,164 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+
+#if VIPS_MAJOR_VERSION > 8 || (VIPS_MAJOR_VERSION == 8 && VIPS_MINOR_VERSION >= 9)
+extern gint64 imgvipsTargetWrite(void *target, void *data, gint64 length);
+
+static gint64 imgvips_target_write(VipsTargetCustom *target, void *data, gint64 length, void *user) {
+	return imgvipsTargetWrite(target, data, length);
+}
+
+static GType imgvips_target_get_type(void) {
+	return vips_target_get_type();
+}
+
+static void *imgvips_target_custom_new(void) {
+	VipsTargetCustom *target = vips_target_custom_new();
+	g_signal_connect(target, "write", G_CALLBACK(imgvips_target_write), NULL);
+
+	return target;
+}
+#else
+static GType imgvips_target_get_type(void) {
+	return G_TYPE_NONE;
+}
+
+static void *imgvips_target_custom_new(void) {
+	return NULL;
+}
+#endif
+*/
+import "C"
+
+import (
+	"io"
+	"sync"
+	"unsafe"
+)
+
+var targetWriters = &writersRegistry{
+	writers: make(map[unsafe.Pointer]*targetWriter),
+}
+
+type targetWriter struct {
+	w   io.Writer
+	err error
+}
+
+type writersRegistry struct {
+	writers map[unsafe.Pointer]*targetWriter
+	mu      sync.RWMutex
+}
+
+func (r *writersRegistry) add(target unsafe.Pointer, w io.Writer) {
+	r.mu.Lock()
+	r.writers[target] = &targetWriter{w: w}
+	r.mu.Unlock()
+}
+
+func (r *writersRegistry) get(target unsafe.Pointer) *targetWriter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.writers[target]
+}
+
+func (r *writersRegistry) remove(target unsafe.Pointer) {
+	r.mu.Lock()
+	delete(r.writers, target)
+	r.mu.Unlock()
+}
+
+// write passes chunk of encoded data from libvips to io.Writer.
+// First write error is kept and returned from *Operation.Exec().
+func (tw *targetWriter) write(data []byte) int {
+	if tw.err != nil {
+		return -1
+	}
+
+	n, err := tw.w.Write(data)
+	if err == nil && n < len(data) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		tw.err = err
+
+		return -1
+	}
+
+	return n
+}
+
+// GVipsTargetToWriter create VipsTargetCustom, which streams all data written by libvips to w.
+//
+// VipsTarget is used in *save_target operations, e.g. jpegsave_target or webpsave_target.
+// If w returns error, operation fails and *Operation.Exec() returns that error.
+// Writer is not closed after save.
+//
+// Requires libvips 8.9+, otherwise ErrNotSupported will be returned.
+//
+// Calling Copy() at GValue with type VipsTarget is forbidden.
+func GVipsTargetToWriter(w io.Writer) (*GValue, error) {
+	target := C.imgvips_target_custom_new()
+	if target == nil {
+		return nil, ErrNotSupported
+	}
+
+	var gValue C.GValue
+
+	v := &GValue{
+		gType:  C.imgvips_target_get_type(),
+		gValue: &gValue,
+		free:   gVipsTargetFree,
+		copy: func(val *GValue) (*GValue, error) {
+			return nil, ErrCopyForbidden
+		},
+	}
+
+	v.init()
+	C.g_value_set_object(v.gValue, C.gpointer(target))
+	C.g_object_unref(C.gpointer(target))
+
+	targetWriters.add(target, w)
+
+	return v, nil
+}
+
+func gVipsTargetFree(val *GValue) {
+	if val.gValue == nil {
+		return
+	}
+
+	ptr := C.g_value_peek_pointer(val.gValue)
+	if ptr != nil {
+		targetWriters.remove(unsafe.Pointer(ptr))
+	}
+	C.g_value_unset(val.gValue)
+	val.gType = C.G_TYPE_NONE
+}
+
+// targetError return error returned by io.Writer, if value is VipsTarget
+func (v *GValue) targetError() error {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.gValue == nil || v.gType == C.G_TYPE_NONE || v.gType != C.imgvips_target_get_type() {
+		return nil
+	}
+
+	ptr := C.g_value_peek_pointer(v.gValue)
+	if ptr == nil {
+		return nil
+	}
+
+	tw := targetWriters.get(unsafe.Pointer(ptr))
+	if tw == nil {
+		return nil
+	}
+
+	return tw.err
+}
@@ -0,0 +1,88 @@
+package imgvips_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestGVipsTargetToWriter(t *testing.T) {
+	initVips(t)
+
+	buf := &bytes.Buffer{}
+	target, err := imgvips.GVipsTargetToWriter(buf)
+	if err == imgvips.ErrNotSupported {
+		t.Skip("VipsTarget is not supported by libvips version")
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	in, loadOp := webpLoadBytes(t)
+	defer loadOp.Free()
+
+	op, err := imgvips.NewOperation("pngsave_target")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer op.Free()
+
+	op.AddInput("in", in)
+	op.AddInput("target", target)
+
+	if err := op.Exec(); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), []byte("\x89PNG")) {
+		t.Fatalf("Expected png data, got %d bytes", buf.Len())
+	}
+
+	_, err = target.Copy()
+	if err != imgvips.ErrCopyForbidden {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrCopyForbidden, err)
+	}
+
+	// Check multiply free
+	target.Free()
+	target.Free()
+}
+
+func TestGVipsTargetToWriter_Error(t *testing.T) {
+	initVips(t)
+
+	writeErr := errors.New("write failed")
+	target, err := imgvips.GVipsTargetToWriter(failingWriter{err: writeErr})
+	if err == imgvips.ErrNotSupported {
+		t.Skip("VipsTarget is not supported by libvips version")
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	in, loadOp := webpLoadBytes(t)
+	defer loadOp.Free()
+
+	op, err := imgvips.NewOperation("pngsave_target")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer op.Free()
+
+	op.AddInput("in", in)
+	op.AddInput("target", target)
+
+	if err := op.Exec(); err != writeErr {
+		t.Fatalf("Expected error %v, got %v", writeErr, err)
+	}
+}
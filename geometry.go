@@ -0,0 +1,141 @@
+package imgvips
+
+// ResizeOptions are optional arguments of Resize(), zero value means libvips defaults
+type ResizeOptions struct {
+	// VScale is vertical scale, if 0, scale is used
+	VScale float64
+	// Kernel is resampling kernel, zero value is KernelDefault
+	Kernel Kernel
+}
+
+// Resize resizes image by scale. If opts is nil, libvips defaults are used.
+// If Kernel is set, but libvips resize has no kernel argument, ErrNotSupported will be returned.
+//
+// Returned value is owned by caller, call *GValue.Free() after image is no longer needed.
+// Same applies to all other geometric methods.
+func (i *Image) Resize(scale float64, opts *ResizeOptions) (*GValue, error) {
+	args := Args{"scale": scale}
+	if opts != nil {
+		if opts.Kernel != KernelDefault {
+			if !HasArgument("resize", "kernel") {
+				return nil, ErrNotSupported
+			}
+			args["kernel"] = opts.Kernel.value()
+		}
+		if opts.VScale != 0 {
+			args["vscale"] = opts.VScale
+		}
+	}
+
+	return i.transform("resize", args)
+}
+
+// Crop extracts area from image, it is alias of ExtractArea()
+func (i *Image) Crop(left, top, width, height int) (*GValue, error) {
+	return i.transform("crop", Args{"left": left, "top": top, "width": width, "height": height})
+}
+
+// ExtractArea extracts area from image
+func (i *Image) ExtractArea(left, top, width, height int) (*GValue, error) {
+	return i.transform("extract_area", Args{"left": left, "top": top, "width": width, "height": height})
+}
+
+// Embed places image at x, y in larger width*height image.
+// Background is used with ExtendBackground, nil means black.
+func (i *Image) Embed(x, y, width, height int, extend Extend, background []float64) (*GValue, error) {
+	args := Args{"x": x, "y": y, "width": width, "height": height, "extend": int(extend)}
+	if background != nil {
+		args["background"] = background
+	}
+
+	return i.transform("embed", args)
+}
+
+// Gravity places image in larger width*height image at position set by direction.
+// Background is used with ExtendBackground, nil means black.
+//
+// Requires libvips 8.6+, otherwise ErrNotSupported will be returned.
+func (i *Image) Gravity(direction CompassDirection, width, height int, extend Extend, background []float64) (*GValue, error) {
+	args := Args{"direction": int(direction), "width": width, "height": height, "extend": int(extend)}
+	if background != nil {
+		args["background"] = background
+	}
+
+	return i.transform("gravity", args)
+}
+
+// Flip flips image horizontally or vertically
+func (i *Image) Flip(direction Direction) (*GValue, error) {
+	return i.transform("flip", Args{"direction": int(direction)})
+}
+
+// Rot rotates image by multiple of 90 degrees
+func (i *Image) Rot(angle Angle) (*GValue, error) {
+	return i.transform("rot", Args{"angle": int(angle)})
+}
+
+// Rotate rotates image by any angle in degrees. Background fills new pixels, nil means black.
+//
+// Requires libvips 8.6+, otherwise ErrNotSupported will be returned.
+func (i *Image) Rotate(angle float64, background []float64) (*GValue, error) {
+	args := Args{"angle": angle}
+	if background != nil {
+		args["background"] = background
+	}
+
+	return i.transform("rotate", args)
+}
+
+// Zoom enlarges image by repeating pixels xfac times horizontally and yfac times vertically
+func (i *Image) Zoom(xfac, yfac int) (*GValue, error) {
+	return i.transform("zoom", Args{"xfac": xfac, "yfac": yfac})
+}
+
+// Shrink shrinks image by averaging pixels, hshrink and vshrink are shrink factors
+func (i *Image) Shrink(hshrink, vshrink float64) (*GValue, error) {
+	return i.transform("shrink", shrinkArgs("shrink", hshrink, vshrink))
+}
+
+// Reduce shrinks image with resampling kernel, hshrink and vshrink are shrink factors.
+// If kernel is set, but libvips reduce has no kernel argument, ErrNotSupported will be returned.
+func (i *Image) Reduce(hshrink, vshrink float64, kernel Kernel) (*GValue, error) {
+	args := shrinkArgs("reduce", hshrink, vshrink)
+	if kernel != KernelDefault {
+		if !HasArgument("reduce", "kernel") {
+			return nil, ErrNotSupported
+		}
+		args["kernel"] = kernel.value()
+	}
+
+	return i.transform("reduce", args)
+}
+
+// shrinkArgs return shrink factors arguments of operation, libvips before 8.5 names them xshrink and yshrink
+func shrinkArgs(name string, hshrink, vshrink float64) Args {
+	if HasArgument(name, "hshrink") {
+		return Args{"hshrink": hshrink, "vshrink": vshrink}
+	}
+
+	return Args{"xshrink": hshrink, "yshrink": vshrink}
+}
+
+// transform executes operation with image as main input and return owned main output
+func (i *Image) transform(name string, args Args) (*GValue, error) {
+	if i.val.wasFreed() {
+		return nil, ErrImageAlreadyFreed
+	}
+	if !HasOperation(name) {
+		return nil, ErrNotSupported
+	}
+
+	in := i.val.ref()
+	out, op, err := runStep(name, args, in)
+	// Input is freed by operation, if it was executed
+	in.Free()
+	if err != nil {
+		return nil, err
+	}
+	defer op.Free()
+
+	return out.ref(), nil
+}
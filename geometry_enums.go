@@ -0,0 +1,80 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "vips/vips.h"
+*/
+import "C"
+
+// Kernel is resampling kernel, see VipsKernel.
+// Values are set without C constants, because enum is missing in libvips 8.2 headers,
+// and shifted by one, so zero value is KernelDefault.
+type Kernel int
+
+// Available kernels
+const (
+	// KernelDefault means default kernel of operation, kernel argument is not set
+	KernelDefault Kernel = iota
+	KernelNearest
+	KernelLinear
+	KernelCubic
+	KernelMitchell
+	KernelLanczos2
+	KernelLanczos3
+)
+
+// value return VipsKernel value, e.g. KernelNearest is VIPS_KERNEL_NEAREST (0).
+// KernelDefault has no VipsKernel value, so it must not be set as argument.
+func (k Kernel) value() int {
+	return int(k) - 1
+}
+
+// Direction is flip direction, see VipsDirection
+type Direction int
+
+// Available directions
+const (
+	DirectionHorizontal Direction = C.VIPS_DIRECTION_HORIZONTAL
+	DirectionVertical   Direction = C.VIPS_DIRECTION_VERTICAL
+)
+
+// Angle is fixed rotate angle, see VipsAngle
+type Angle int
+
+// Available angles
+const (
+	AngleD0   Angle = C.VIPS_ANGLE_D0
+	AngleD90  Angle = C.VIPS_ANGLE_D90
+	AngleD180 Angle = C.VIPS_ANGLE_D180
+	AngleD270 Angle = C.VIPS_ANGLE_D270
+)
+
+// Extend is how to generate new pixels at image edges, see VipsExtend
+type Extend int
+
+// Available extend modes
+const (
+	ExtendBlack      Extend = C.VIPS_EXTEND_BLACK
+	ExtendCopy       Extend = C.VIPS_EXTEND_COPY
+	ExtendRepeat     Extend = C.VIPS_EXTEND_REPEAT
+	ExtendMirror     Extend = C.VIPS_EXTEND_MIRROR
+	ExtendWhite      Extend = C.VIPS_EXTEND_WHITE
+	ExtendBackground Extend = C.VIPS_EXTEND_BACKGROUND
+)
+
+// CompassDirection is image placement, see VipsCompassDirection.
+// Values are set without C constants, because enum was added in libvips 8.6.
+type CompassDirection int
+
+// Available compass directions
+const (
+	CompassDirectionCentre CompassDirection = iota
+	CompassDirectionNorth
+	CompassDirectionEast
+	CompassDirectionSouth
+	CompassDirectionWest
+	CompassDirectionNorthEast
+	CompassDirectionSouthEast
+	CompassDirectionSouthWest
+	CompassDirectionNorthWest
+)
@@ -0,0 +1,91 @@
+package imgvips_test
+
+import (
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestImage_Geometry(t *testing.T) {
+	initVips(t)
+
+	val, op := generateImage(t)
+	defer op.Free()
+
+	img, ok := val.Image()
+	if !ok || img == nil {
+		t.Fatal("Expected *C.VipsImage in val")
+	}
+
+	cases := []struct {
+		name          string
+		transform     func() (*imgvips.GValue, error)
+		width, height int
+	}{
+		{"resize", func() (*imgvips.GValue, error) { return img.Resize(0.5, nil) }, 50, 50},
+		{"resize options", func() (*imgvips.GValue, error) {
+			return img.Resize(0.5, &imgvips.ResizeOptions{VScale: 0.25})
+		}, 50, 25},
+		{"resize kernel", func() (*imgvips.GValue, error) {
+			return img.Resize(0.5, &imgvips.ResizeOptions{Kernel: imgvips.KernelNearest})
+		}, 50, 50},
+		{"crop", func() (*imgvips.GValue, error) { return img.Crop(10, 20, 30, 40) }, 30, 40},
+		{"extract area", func() (*imgvips.GValue, error) { return img.ExtractArea(10, 20, 30, 40) }, 30, 40},
+		{"embed", func() (*imgvips.GValue, error) {
+			return img.Embed(10, 10, 120, 130, imgvips.ExtendBackground, []float64{255})
+		}, 120, 130},
+		{"gravity", func() (*imgvips.GValue, error) {
+			return img.Gravity(imgvips.CompassDirectionNorth, 120, 130, imgvips.ExtendWhite, nil)
+		}, 120, 130},
+		{"flip", func() (*imgvips.GValue, error) { return img.Flip(imgvips.DirectionVertical) }, 100, 100},
+		{"rot", func() (*imgvips.GValue, error) {
+			// Non-square image, so swapped width and height are checked
+			rect, err := img.Crop(0, 0, 100, 40)
+			if err != nil {
+				return nil, err
+			}
+			defer rect.Free()
+
+			rectImg, _ := rect.Image()
+
+			return rectImg.Rot(imgvips.AngleD90)
+		}, 40, 100},
+		// Size of bounding box depends on interpolation, so it is not checked
+		{"rotate", func() (*imgvips.GValue, error) { return img.Rotate(45, []float64{255}) }, 0, 0},
+		{"zoom", func() (*imgvips.GValue, error) { return img.Zoom(2, 3) }, 200, 300},
+		// Shrink factors are xshrink and yshrink before libvips 8.5, e.g. in CI images
+		{"shrink", func() (*imgvips.GValue, error) { return img.Shrink(2, 4) }, 50, 25},
+		{"reduce default kernel", func() (*imgvips.GValue, error) { return img.Reduce(2, 4, imgvips.KernelDefault) }, 50, 25},
+		{"reduce", func() (*imgvips.GValue, error) { return img.Reduce(2, 4, imgvips.KernelLinear) }, 50, 25},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out, err := c.transform()
+			if err == imgvips.ErrNotSupported {
+				t.Skip("operation is not supported by libvips")
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			defer out.Free()
+
+			result, ok := out.Image()
+			if !ok || result == nil {
+				t.Fatal("Expected *C.VipsImage in out")
+			}
+			if c.width > 0 && (result.Width() != c.width || result.Height() != c.height) {
+				t.Errorf("Expected size %dx%d, got %dx%d", c.width, c.height, result.Width(), result.Height())
+			}
+		})
+	}
+
+	if _, err := img.Crop(90, 90, 30, 30); err == nil {
+		t.Error("Expected to return error, got nil")
+	}
+
+	val.Free()
+	if _, err := img.Resize(0.5, nil); err != imgvips.ErrImageAlreadyFreed {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrImageAlreadyFreed, err)
+	}
+}
@@ -0,0 +1,330 @@
+package imgvips
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrUnknownNode returns when graph node or output refers to node, which was not added
+	ErrUnknownNode = errors.New("unknown graph node")
+	// ErrDuplicateNode returns when graph node name is already used
+	ErrDuplicateNode = errors.New("duplicate graph node")
+	// ErrGraphCycle returns when graph nodes depend on each other
+	ErrGraphCycle = errors.New("graph has cycle")
+	// ErrNodeSkipped returns by NodeError, when node was not executed because other node failed
+	ErrNodeSkipped = errors.New("node skipped")
+)
+
+// NodeError returns when graph node fails
+type NodeError struct {
+	Node      string
+	Operation string
+	Err       error
+}
+
+func (e *NodeError) Error() string {
+	return fmt.Sprintf("node %s (%s): %v", e.Node, e.Operation, e.Err)
+}
+
+// Unwrap return underlying error
+func (e *NodeError) Unwrap() error {
+	return e.Err
+}
+
+// Inputs maps operation image arguments to names of graph nodes, e.g. {"base": "background", "overlay": "logo"}
+type Inputs map[string]string
+
+type graphNode struct {
+	name      string
+	operation string
+	isSource  bool
+	source    interface{}
+	inputs    Inputs
+	args      Args
+
+	// consumers is number of references to node output: one per dependent input and one if node is graph output
+	consumers int64
+	// unused node output is freed right after execution
+	unused bool
+	deps   []*graphNode
+
+	out  *GValue
+	op   *Operation
+	err  error
+	done chan struct{}
+}
+
+// release drops one reference to node output and frees output after last one
+func (n *graphNode) release() {
+	if atomic.AddInt64(&n.consumers, -1) > 0 {
+		return
+	}
+
+	if n.out != nil {
+		n.out.Free()
+	}
+	freeOperation(n.op)
+}
+
+// Graph describes operations with image dependencies between them.
+//
+// Independent nodes are executed in parallel, limited by SetMaxInFlight().
+// Output image of node is shared between dependent nodes by reference and freed after last of them is built.
+// Graph is not safe for concurrent use.
+type Graph struct {
+	nodes   map[string]*graphNode
+	order   []string
+	outputs []string
+	err     error
+}
+
+// NewGraph create empty graph
+func NewGraph() *Graph {
+	return &Graph{nodes: make(map[string]*graphNode)}
+}
+
+// Source adds node, which loads image from source. Source types are described in *Pipeline.Load().
+func (g *Graph) Source(name string, source interface{}) *Graph {
+	return g.add(&graphNode{name: name, operation: "load", isSource: true, source: source})
+}
+
+// Node adds operation node.
+//
+// Inputs set outputs of other nodes to operation image arguments, args are converted as described in Args.
+// Node output is main output image of operation, e.g. out.
+func (g *Graph) Node(name, operation string, inputs Inputs, args Args) *Graph {
+	return g.add(&graphNode{name: name, operation: operation, inputs: inputs, args: args})
+}
+
+// Output marks nodes, which output images will be returned by Exec()
+func (g *Graph) Output(names ...string) *Graph {
+	g.outputs = append(g.outputs, names...)
+
+	return g
+}
+
+func (g *Graph) add(node *graphNode) *Graph {
+	if _, ok := g.nodes[node.name]; ok && g.err == nil {
+		g.err = fmt.Errorf("%w: %s", ErrDuplicateNode, node.name)
+	}
+
+	g.nodes[node.name] = node
+	g.order = append(g.order, node.name)
+
+	return g
+}
+
+// Exec executes graph and return output images by node names.
+//
+// Returned values are owned by caller, call *GValue.Free() on each of them after image is no longer needed.
+// If any node fails, other nodes are skipped, all images are freed and *NodeError of first failed node returned.
+func (g *Graph) Exec() (map[string]*GValue, error) {
+	nodes, err := g.prepare()
+	if err != nil {
+		return nil, err
+	}
+
+	var wg sync.WaitGroup
+	var failed int32
+
+	wg.Add(len(nodes))
+	for _, node := range nodes {
+		go func(node *graphNode) {
+			defer wg.Done()
+			defer close(node.done)
+
+			for _, dep := range node.deps {
+				<-dep.done
+			}
+
+			if atomic.LoadInt32(&failed) != 0 {
+				node.err = ErrNodeSkipped
+			} else {
+				asyncExecutor.acquire()
+				node.out, node.op, node.err = node.exec()
+				asyncExecutor.release()
+			}
+
+			if node.err != nil {
+				atomic.StoreInt32(&failed, 1)
+			}
+
+			// Node operation holds own references to input images
+			for _, dep := range node.deps {
+				dep.release()
+			}
+			if node.unused {
+				node.release()
+			}
+		}(node)
+	}
+	wg.Wait()
+
+	result := make(map[string]*GValue, len(g.outputs))
+	for _, name := range g.outputs {
+		if _, ok := result[name]; !ok && atomic.LoadInt32(&failed) == 0 {
+			result[name] = g.nodes[name].out.ref()
+		}
+		g.nodes[name].release()
+	}
+
+	if atomic.LoadInt32(&failed) != 0 {
+		return nil, g.firstError()
+	}
+
+	return result, nil
+}
+
+// prepare validates graph and resets nodes state
+func (g *Graph) prepare() ([]*graphNode, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	nodes := make([]*graphNode, 0, len(g.order))
+	for _, name := range g.order {
+		node := g.nodes[name]
+		node.deps = node.deps[:0]
+		node.consumers = 0
+		node.out, node.op, node.err = nil, nil, nil
+		node.done = make(chan struct{})
+
+		nodes = append(nodes, node)
+	}
+
+	for _, node := range nodes {
+		for _, arg := range sortedInputs(node.inputs) {
+			dep, ok := g.nodes[node.inputs[arg]]
+			if !ok {
+				return nil, &NodeError{Node: node.name, Operation: node.operation, Err: fmt.Errorf("%w: %s", ErrUnknownNode, node.inputs[arg])}
+			}
+			node.deps = append(node.deps, dep)
+			dep.consumers++
+		}
+	}
+	for _, name := range g.outputs {
+		node, ok := g.nodes[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNode, name)
+		}
+		node.consumers++
+	}
+	for _, node := range nodes {
+		node.unused = node.consumers == 0
+		if node.unused {
+			node.consumers = 1
+		}
+	}
+
+	if err := checkCycles(nodes); err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// firstError return error of first failed node in order of adding
+func (g *Graph) firstError() error {
+	for _, name := range g.order {
+		node := g.nodes[name]
+		if node.err != nil && node.err != ErrNodeSkipped {
+			return &NodeError{Node: node.name, Operation: node.operation, Err: node.err}
+		}
+	}
+
+	return nil
+}
+
+func (n *graphNode) exec() (*GValue, *Operation, error) {
+	if n.isSource {
+		return loadSource(n.source)
+	}
+
+	op, err := NewOperation(n.operation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, output := op.mainImageArguments()
+	if output == "" {
+		op.Free()
+
+		return nil, nil, ErrNoImageArgument
+	}
+
+	names, values, err := n.args.values()
+	if err != nil {
+		op.Free()
+
+		return nil, nil, err
+	}
+
+	for i, arg := range sortedInputs(n.inputs) {
+		op.AddInput(arg, n.deps[i].out.ref())
+	}
+	for i, name := range names {
+		op.AddInput(name, values[i])
+	}
+
+	out := GNullVipsImage()
+	op.AddOutput(output, out)
+
+	if err := op.Exec(); err != nil {
+		op.Free()
+
+		return nil, nil, err
+	}
+
+	return out, op, nil
+}
+
+// checkCycles checks, that all nodes can be ordered by dependencies
+func checkCycles(nodes []*graphNode) error {
+	pending := make(map[*graphNode]int, len(nodes))
+	dependents := make(map[*graphNode][]*graphNode, len(nodes))
+	var ready []*graphNode
+
+	for _, node := range nodes {
+		pending[node] = len(node.deps)
+		for _, dep := range node.deps {
+			dependents[dep] = append(dependents[dep], node)
+		}
+		if len(node.deps) == 0 {
+			ready = append(ready, node)
+		}
+	}
+
+	visited := 0
+	for len(ready) > 0 {
+		node := ready[0]
+		ready = ready[1:]
+		visited++
+
+		for _, dependent := range dependents[node] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if visited != len(nodes) {
+		return ErrGraphCycle
+	}
+
+	return nil
+}
+
+func sortedInputs(inputs Inputs) []string {
+	args := make([]string, 0, len(inputs))
+	for arg := range inputs {
+		args = append(args, arg)
+	}
+	sort.Strings(args)
+
+	return args
+}
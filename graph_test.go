@@ -0,0 +1,123 @@
+package imgvips_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestGraph_Exec(t *testing.T) {
+	initVips(t)
+
+	in, op := generateImage(t)
+	defer op.Free()
+
+	before := imgvips.GetStats()
+
+	outputs, err := imgvips.NewGraph().
+		Source("decoded", in).
+		Node("small", "resize", imgvips.Inputs{"in": "decoded"}, imgvips.Args{"scale": 0.5}).
+		Node("tiny", "resize", imgvips.Inputs{"in": "decoded"}, imgvips.Args{"scale": 0.25}).
+		Node("unused", "invert", imgvips.Inputs{"in": "decoded"}, nil).
+		Node("background", "gaussblur", imgvips.Inputs{"in": "decoded"}, imgvips.Args{"sigma": 5.0}).
+		Node("composed", "insert", imgvips.Inputs{"main": "background", "sub": "tiny"}, imgvips.Args{"x": 10, "y": 10}).
+		Output("small", "tiny", "composed").
+		Exec()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	expected := map[string]int{"small": 50, "tiny": 25, "composed": 100}
+	if len(outputs) != len(expected) {
+		t.Fatalf("Expected %d outputs, got %d", len(expected), len(outputs))
+	}
+	for name, width := range expected {
+		img, ok := outputs[name].Image()
+		if !ok || img == nil {
+			t.Fatalf("Expected *C.VipsImage in %s", name)
+		}
+		if img.Width() != width {
+			t.Errorf("Expected %s width %d, got %d", name, width, img.Width())
+		}
+	}
+
+	for _, val := range outputs {
+		val.Free()
+	}
+
+	after := imgvips.GetStats()
+	if after.Operations != before.Operations || after.Values != before.Values {
+		t.Errorf("Expected %d operations and %d values, got %d and %d",
+			before.Operations, before.Values, after.Operations, after.Values)
+	}
+}
+
+func TestGraph_ExecError(t *testing.T) {
+	initVips(t)
+
+	in, op := generateImage(t)
+	defer op.Free()
+
+	cases := []struct {
+		name  string
+		graph *imgvips.Graph
+		err   error
+		node  string
+	}{
+		{
+			"duplicate",
+			imgvips.NewGraph().Source("a", in).Source("a", in),
+			imgvips.ErrDuplicateNode, "",
+		},
+		{
+			"unknown input",
+			imgvips.NewGraph().Source("a", in).Node("b", "invert", imgvips.Inputs{"in": "c"}, nil),
+			imgvips.ErrUnknownNode, "b",
+		},
+		{
+			"unknown output",
+			imgvips.NewGraph().Source("a", in).Output("b"),
+			imgvips.ErrUnknownNode, "",
+		},
+		{
+			"cycle",
+			imgvips.NewGraph().
+				Node("a", "invert", imgvips.Inputs{"in": "b"}, nil).
+				Node("b", "invert", imgvips.Inputs{"in": "a"}, nil),
+			imgvips.ErrGraphCycle, "",
+		},
+		{
+			"failed node",
+			imgvips.NewGraph().
+				Source("a", in).
+				Node("b", "resize", imgvips.Inputs{"in": "a"}, nil).
+				Node("c", "invert", imgvips.Inputs{"in": "b"}, nil).
+				Output("c"),
+			nil, "b",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.graph.Exec()
+			if err == nil {
+				t.Fatal("Expected to return error, got nil")
+			}
+			if c.err != nil && !errors.Is(err, c.err) {
+				t.Errorf("Expected error %v, got %v", c.err, err)
+			}
+
+			var nodeErr *imgvips.NodeError
+			if c.node == "" {
+				return
+			}
+			if !errors.As(err, &nodeErr) {
+				t.Fatalf("Expected *imgvips.NodeError, got %v", err)
+			}
+			if nodeErr.Node != c.node {
+				t.Errorf("Expected node %s, got %s", c.node, nodeErr.Node)
+			}
+		})
+	}
+}
@@ -3,12 +3,30 @@ package imgvips
 /*
 #cgo pkg-config: vips
 #include "vips/vips.h"
+
+static int imgvips_image_get_int(VipsImage *image, const char *name, int def) {
+	int value;
+
+	if (vips_image_get_typeof(image, name) == 0 || vips_image_get_int(image, name, &value)) {
+		vips_error_clear();
+
+		return def;
+	}
+
+	return value;
+}
 */
 import "C"
 import (
+	"errors"
 	"unsafe"
 )
 
+var (
+	// ErrImageAlreadyFreed image value already call Free()
+	ErrImageAlreadyFreed = errors.New("image already freed")
+)
+
 // Image wrapper around *C.VipsImage
 type Image struct {
 	image *C.VipsImage
@@ -40,3 +58,79 @@ func (i *Image) Height() int {
 
 	return int(C.vips_image_get_height(i.image))
 }
+
+// Bands return number of image bands
+// Return 0 if image was freed
+func (i *Image) Bands() int {
+	if i.val.wasFreed() {
+		return 0
+	}
+
+	return int(C.vips_image_get_bands(i.image))
+}
+
+// Format return image band format
+// Return BandFormatNotSet if image was freed
+func (i *Image) Format() BandFormat {
+	if i.val.wasFreed() {
+		return BandFormatNotSet
+	}
+
+	return BandFormat(C.vips_image_get_format(i.image))
+}
+
+// Interpretation return image interpretation
+// Return InterpretationError if image was freed
+func (i *Image) Interpretation() Interpretation {
+	if i.val.wasFreed() {
+		return InterpretationError
+	}
+
+	return Interpretation(C.vips_image_get_interpretation(i.image))
+}
+
+// Pages return number of pages in image file
+// Return 0 if image was freed
+func (i *Image) Pages() int {
+	if i.val.wasFreed() {
+		return 0
+	}
+
+	return int(C.imgvips_image_get_int(i.image, cStringsCache.get("n-pages"), 1))
+}
+
+// Orientation return EXIF orientation of image, 1 if image has no orientation
+// Return 0 if image was freed
+func (i *Image) Orientation() int {
+	if i.val.wasFreed() {
+		return 0
+	}
+
+	return int(C.imgvips_image_get_int(i.image, cStringsCache.get("orientation"), 1))
+}
+
+// HasICCProfile return true if image has attached ICC profile
+// Return false if image was freed
+func (i *Image) HasICCProfile() bool {
+	if i.val.wasFreed() {
+		return false
+	}
+
+	return C.vips_image_get_typeof(i.image, cStringsCache.get("icc-profile-data")) != 0
+}
+
+// HasAlpha return true if image has alpha band
+// Return false if image was freed
+func (i *Image) HasAlpha() bool {
+	bands := i.Bands()
+	interpretation := i.Interpretation()
+
+	switch {
+	case bands == 2:
+		return interpretation == InterpretationBW || interpretation == InterpretationGrey16
+	case bands == 4:
+		return interpretation != InterpretationCMYK
+	default:
+		return bands > 4
+	}
+}
@@ -0,0 +1,202 @@
+package imgvips
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"unsafe"
+)
+
+var (
+	// ErrUnsupportedPixels returns when image bands count or band format can't be converted to image.Image
+	ErrUnsupportedPixels = errors.New("unsupported bands count or band format")
+)
+
+// nativeEndian is byte order of libvips multi-byte band elements
+var nativeEndian binary.ByteOrder = func() binary.ByteOrder {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return binary.LittleEndian
+	}
+
+	return binary.BigEndian
+}()
+
+// FromGoImage create *C.VipsImage from image.Image.
+//
+// *image.Gray, *image.Gray16, *image.NRGBA, *image.NRGBA64 and opaque *image.RGBA, *image.RGBA64 are copied as is,
+// *image.YCbCr converted to 3 bands RGB, other images are drawn to *image.NRGBA or *image.NRGBA64 before copy.
+// Alpha-premultiplied images with transparency are converted to non-premultiplied, as libvips expects.
+func FromGoImage(img image.Image) (*GValue, error) {
+	switch src := img.(type) {
+	case *image.Gray:
+		return fromGoPix(src.Pix, src.Stride, src.Rect, 1, 1)
+	case *image.Gray16:
+		return fromGoPix(src.Pix, src.Stride, src.Rect, 1, 2)
+	case *image.NRGBA:
+		return fromGoPix(src.Pix, src.Stride, src.Rect, 4, 1)
+	case *image.NRGBA64:
+		return fromGoPix(src.Pix, src.Stride, src.Rect, 4, 2)
+	case *image.RGBA:
+		if src.Opaque() {
+			return fromGoPix(src.Pix, src.Stride, src.Rect, 4, 1)
+		}
+	case *image.RGBA64:
+		if src.Opaque() {
+			return fromGoPix(src.Pix, src.Stride, src.Rect, 4, 2)
+		}
+		dst := image.NewNRGBA64(src.Rect)
+		draw.Draw(dst, dst.Rect, src, src.Rect.Min, draw.Src)
+
+		return fromGoPix(dst.Pix, dst.Stride, dst.Rect, 4, 2)
+	case *image.YCbCr:
+		return fromYCbCr(src)
+	}
+
+	dst := image.NewNRGBA(img.Bounds())
+	draw.Draw(dst, dst.Rect, img, img.Bounds().Min, draw.Src)
+
+	return fromGoPix(dst.Pix, dst.Stride, dst.Rect, 4, 1)
+}
+
+// fromGoPix copy rows of Go pixels buffer to tightly packed buffer and create image from it.
+// Go stores 16 bit samples in big-endian, so they are converted to native byte order.
+func fromGoPix(pix []byte, stride int, rect image.Rectangle, bands, sampleSize int) (*GValue, error) {
+	width, height := rect.Dx(), rect.Dy()
+	if width <= 0 || height <= 0 {
+		return nil, ErrInvalidGeometry
+	}
+
+	rowSize := width * bands * sampleSize
+	data := make([]byte, rowSize*height)
+	for y := 0; y < height; y++ {
+		row := data[y*rowSize : (y+1)*rowSize]
+		copy(row, pix[y*stride:y*stride+rowSize])
+
+		if sampleSize == 2 && nativeEndian != binary.BigEndian {
+			for i := 0; i < len(row); i += 2 {
+				row[i], row[i+1] = row[i+1], row[i]
+			}
+		}
+	}
+
+	format := BandFormatUchar
+	if sampleSize == 2 {
+		format = BandFormatUshort
+	}
+
+	return NewImageFromMemory(data, width, height, bands, format)
+}
+
+func fromYCbCr(src *image.YCbCr) (*GValue, error) {
+	width, height := src.Rect.Dx(), src.Rect.Dy()
+	if width <= 0 || height <= 0 {
+		return nil, ErrInvalidGeometry
+	}
+
+	data := make([]byte, 0, width*height*3)
+	for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+		for x := src.Rect.Min.X; x < src.Rect.Max.X; x++ {
+			c := src.YCbCrAt(x, y)
+			r, g, b := color.YCbCrToRGB(c.Y, c.Cb, c.Cr)
+			data = append(data, r, g, b)
+		}
+	}
+
+	return NewImageFromMemory(data, width, height, 3, BandFormatUchar)
+}
+
+// ToGoImage computes image and return its pixels as image.Image.
+//
+// Image must be uchar or ushort, with 1 (grey), 2 (grey with alpha), 3 (RGB) or 4 (RGBA) bands.
+// Returned type depends on bands and format:
+//   - uchar: *image.Gray, *image.NRGBA, *image.RGBA, *image.NRGBA
+//   - ushort: *image.Gray16, *image.NRGBA64, *image.RGBA64, *image.NRGBA64
+//
+// Bands are used as is, so convert image to sRGB or B_W colourspace before call if needed.
+func (i *Image) ToGoImage() (image.Image, error) {
+	format := i.Format()
+	bands := i.Bands()
+	if (format != BandFormatUchar && format != BandFormatUshort) || bands < 1 || bands > 4 {
+		if i.val.wasFreed() {
+			return nil, ErrImageAlreadyFreed
+		}
+
+		return nil, ErrUnsupportedPixels
+	}
+
+	pixels, err := i.WriteToMemory()
+	if err != nil {
+		return nil, err
+	}
+
+	if pixels.Format == BandFormatUshort {
+		return toGoImage16(pixels), nil
+	}
+
+	return toGoImage8(pixels), nil
+}
+
+func toGoImage8(pixels Pixels) image.Image {
+	rect := image.Rect(0, 0, pixels.Width, pixels.Height)
+	src := pixels.Data
+
+	switch pixels.Bands {
+	case 1:
+		return &image.Gray{Pix: src, Stride: pixels.Width, Rect: rect}
+	case 2:
+		dst := image.NewNRGBA(rect)
+		for s, d := 0, 0; s < len(src); s, d = s+2, d+4 {
+			dst.Pix[d], dst.Pix[d+1], dst.Pix[d+2], dst.Pix[d+3] = src[s], src[s], src[s], src[s+1]
+		}
+
+		return dst
+	case 3:
+		dst := image.NewRGBA(rect)
+		for s, d := 0, 0; s < len(src); s, d = s+3, d+4 {
+			dst.Pix[d], dst.Pix[d+1], dst.Pix[d+2], dst.Pix[d+3] = src[s], src[s+1], src[s+2], 0xff
+		}
+
+		return dst
+	default:
+		return &image.NRGBA{Pix: src, Stride: pixels.Width * 4, Rect: rect}
+	}
+}
+
+func toGoImage16(pixels Pixels) image.Image {
+	rect := image.Rect(0, 0, pixels.Width, pixels.Height)
+	src := pixels.Data
+
+	// Convert samples from native byte order to big-endian, used by image package
+	samples := make([]byte, len(src))
+	for s := 0; s < len(src); s += 2 {
+		binary.BigEndian.PutUint16(samples[s:], nativeEndian.Uint16(src[s:]))
+	}
+
+	switch pixels.Bands {
+	case 1:
+		return &image.Gray16{Pix: samples, Stride: pixels.Width * 2, Rect: rect}
+	case 2:
+		dst := image.NewNRGBA64(rect)
+		for s, d := 0, 0; s < len(samples); s, d = s+4, d+8 {
+			copy(dst.Pix[d:], samples[s:s+2])
+			copy(dst.Pix[d+2:], samples[s:s+2])
+			copy(dst.Pix[d+4:], samples[s:s+2])
+			copy(dst.Pix[d+6:], samples[s+2:s+4])
+		}
+
+		return dst
+	case 3:
+		dst := image.NewRGBA64(rect)
+		for s, d := 0, 0; s < len(samples); s, d = s+6, d+8 {
+			copy(dst.Pix[d:], samples[s:s+6])
+			dst.Pix[d+6], dst.Pix[d+7] = 0xff, 0xff
+		}
+
+		return dst
+	default:
+		return &image.NRGBA64{Pix: samples, Stride: pixels.Width * 8, Rect: rect}
+	}
+}
@@ -0,0 +1,112 @@
+package imgvips_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestFromGoImage(t *testing.T) {
+	initVips(t)
+
+	nrgba := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	nrgba.SetNRGBA(1, 1, color.NRGBA{R: 10, G: 20, B: 30, A: 40})
+
+	gray16 := image.NewGray16(image.Rect(0, 0, 3, 2))
+	gray16.SetGray16(2, 1, color.Gray16{Y: 0x1234})
+
+	rgba := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	rgba.SetRGBA(0, 0, color.RGBA{R: 50, G: 50, B: 50, A: 100})
+
+	ycbcr := image.NewYCbCr(image.Rect(0, 0, 3, 2), image.YCbCrSubsampleRatio444)
+	for i := range ycbcr.Cb {
+		ycbcr.Cb[i], ycbcr.Cr[i] = 128, 128
+	}
+
+	cases := []struct {
+		name     string
+		img      image.Image
+		x, y     int
+		expected color.Color
+	}{
+		{"nrgba", nrgba, 1, 1, color.NRGBA{R: 10, G: 20, B: 30, A: 40}},
+		{"gray16", gray16, 2, 1, color.Gray16{Y: 0x1234}},
+		{"rgba", rgba, 0, 0, color.RGBA{R: 50, G: 50, B: 50, A: 100}},
+		{"ycbcr", ycbcr, 0, 0, color.RGBA{A: 0xff}},
+		{"paletted", image.NewPaletted(image.Rect(0, 0, 3, 2), color.Palette{color.White}), 0, 0, color.White},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			val, err := imgvips.FromGoImage(c.img)
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			defer val.Free()
+
+			img, ok := val.Image()
+			if !ok {
+				t.Fatal("Expected to be ok")
+			}
+			if img.Width() != 3 || img.Height() != 2 {
+				t.Fatalf("Expected size %dx%d, got %dx%d", 3, 2, img.Width(), img.Height())
+			}
+
+			result, err := img.ToGoImage()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			er, eg, eb, ea := c.expected.RGBA()
+			r, g, b, a := result.At(c.x, c.y).RGBA()
+			if er>>8 != r>>8 || eg>>8 != g>>8 || eb>>8 != b>>8 || ea>>8 != a>>8 {
+				t.Errorf("Expected color %v, got %v", c.expected, result.At(c.x, c.y))
+			}
+		})
+	}
+}
+
+func TestImage_ToGoImage(t *testing.T) {
+	initVips(t)
+
+	val, err := imgvips.NewImageFromMemory([]byte{1, 2, 3, 4, 5, 6}, 2, 1, 3, imgvips.BandFormatUchar)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer val.Free()
+
+	img, ok := val.Image()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+
+	result, err := img.ToGoImage()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	rgba, ok := result.(*image.RGBA)
+	if !ok {
+		t.Fatalf("Expected *image.RGBA, got %T", result)
+	}
+	if rgba.RGBAAt(1, 0) != (color.RGBA{R: 4, G: 5, B: 6, A: 0xff}) {
+		t.Errorf("Expected color %v, got %v", color.RGBA{R: 4, G: 5, B: 6, A: 0xff}, rgba.RGBAAt(1, 0))
+	}
+
+	floatVal, op := generateImage(t)
+	defer op.Free()
+
+	floatImg, ok := floatVal.Image()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+	if _, err := floatImg.ToGoImage(); err != imgvips.ErrUnsupportedPixels {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrUnsupportedPixels, err)
+	}
+
+	floatVal.Free()
+	if _, err := floatImg.ToGoImage(); err != imgvips.ErrImageAlreadyFreed {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrImageAlreadyFreed, err)
+	}
+}
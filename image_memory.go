@@ -0,0 +1,130 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+
+#if VIPS_MAJOR_VERSION == 8 && VIPS_MINOR_VERSION < 3
+static void imgvips_image_memory_free(VipsImage *image, void *data) {
+	free(data);
+}
+
+static VipsImage *imgvips_image_new_from_memory_copy(const void *data, size_t size,
+	int width, int height, int bands, VipsBandFormat format) {
+	void *buf = malloc(size);
+	if (buf == NULL) {
+		return NULL;
+	}
+	memcpy(buf, data, size);
+
+	VipsImage *image = vips_image_new_from_memory(buf, size, width, height, bands, format);
+	if (image == NULL) {
+		free(buf);
+
+		return NULL;
+	}
+	g_signal_connect(image, "postclose", G_CALLBACK(imgvips_image_memory_free), buf);
+
+	return image;
+}
+#else
+static VipsImage *imgvips_image_new_from_memory_copy(const void *data, size_t size,
+	int width, int height, int bands, VipsBandFormat format) {
+	return vips_image_new_from_memory_copy(data, size, width, height, bands, format);
+}
+#endif
+*/
+import "C"
+
+import (
+	"errors"
+	"math"
+	"unsafe"
+)
+
+var (
+	// ErrInvalidGeometry returns when image width, height, bands or band format is invalid
+	ErrInvalidGeometry = errors.New("invalid image geometry")
+	// ErrInvalidPixelsSize returns when pixels buffer size does not match image geometry
+	ErrInvalidPixelsSize = errors.New("pixels buffer size does not match image geometry")
+	// ErrImageTooLarge returns when image pixels don't fit Go slice on current platform
+	ErrImageTooLarge = errors.New("image too large")
+)
+
+// maxInt is maximum value of int on current platform
+const maxInt = int(^uint(0) >> 1)
+
+// Pixels contains raw image pixels and its layout.
+//
+// Data is pixels in row-major order, bands are interleaved, every band element has Format size.
+type Pixels struct {
+	Data   []byte
+	Width  int
+	Height int
+	Bands  int
+	Format BandFormat
+}
+
+// WriteToMemory computes image and return copy of its pixels in Go memory.
+//
+// Result does not depend on image and can be used after image is freed.
+// Pixels are computed to C memory and then copied, so twice the pixels size is used while copying.
+func (i *Image) WriteToMemory() (Pixels, error) {
+	if i.val.wasFreed() {
+		return Pixels{}, ErrImageAlreadyFreed
+	}
+
+	var size C.size_t
+	ptr := C.vips_image_write_to_memory(i.image, &size)
+	if ptr == nil {
+		return Pixels{}, vipsError()
+	}
+	defer C.g_free(C.gpointer(ptr))
+
+	// C.GoBytes takes C.int size, so pixels larger than 2 GiB are copied from slice over C memory
+	if uint64(size) > uint64(maxInt) {
+		return Pixels{}, ErrImageTooLarge
+	}
+	data := make([]byte, int(size))
+	copy(data, cBytes(ptr, int(size)))
+
+	return Pixels{
+		Data:   data,
+		Width:  int(C.vips_image_get_width(i.image)),
+		Height: int(C.vips_image_get_height(i.image)),
+		Bands:  int(C.vips_image_get_bands(i.image)),
+		Format: BandFormat(C.vips_image_get_format(i.image)),
+	}, nil
+}
+
+// NewImageFromMemory create *C.VipsImage from copy of raw pixels.
+//
+// Data must contain width*height*bands band elements of provided format in row-major order with interleaved bands.
+// Data is copied, so it can be modified or collected by GC right after call.
+func NewImageFromMemory(data []byte, width, height, bands int, format BandFormat) (*GValue, error) {
+	if width <= 0 || height <= 0 || bands <= 0 || format.Size() == 0 {
+		return nil, ErrInvalidGeometry
+	}
+	// libvips takes C int dimensions
+	if width > math.MaxInt32 || height > math.MaxInt32 || bands > math.MaxInt32 {
+		return nil, ErrInvalidGeometry
+	}
+	// Size is compared by pixels, so product of all dimensions can't overflow
+	pel := int64(bands) * int64(format.Size())
+	if size := int64(len(data)); size%pel != 0 || size/pel != int64(width)*int64(height) {
+		return nil, ErrInvalidPixelsSize
+	}
+
+	image := C.imgvips_image_new_from_memory_copy(unsafe.Pointer(&data[0]), C.size_t(len(data)),
+		C.int(width), C.int(height), C.int(bands), C.VipsBandFormat(format))
+	if image == nil {
+		return nil, vipsError()
+	}
+
+	v := GNullVipsImage()
+	// Keep reference from image creation, it will be released on Free() as for operation outputs
+	C.g_value_set_object(v.gValue, C.gpointer(image))
+
+	return v, nil
+}
@@ -0,0 +1,113 @@
+package imgvips_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestImage_WriteToMemory(t *testing.T) {
+	initVips(t)
+
+	val, op := generateImage(t)
+	defer op.Free()
+
+	img, ok := val.Image()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+
+	pixels, err := img.WriteToMemory()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if pixels.Width != 100 || pixels.Height != 100 {
+		t.Errorf("Expected size %dx%d, got %dx%d", 100, 100, pixels.Width, pixels.Height)
+	}
+	if pixels.Bands != 1 {
+		t.Errorf("Expected %d bands, got %d", 1, pixels.Bands)
+	}
+	if pixels.Format != imgvips.BandFormatFloat {
+		t.Errorf("Expected format %d, got %d", imgvips.BandFormatFloat, pixels.Format)
+	}
+	expectedSize := pixels.Width * pixels.Height * pixels.Bands * pixels.Format.Size()
+	if len(pixels.Data) != expectedSize {
+		t.Errorf("Expected %d bytes, got %d", expectedSize, len(pixels.Data))
+	}
+
+	val.Free()
+
+	if _, err := img.WriteToMemory(); err != imgvips.ErrImageAlreadyFreed {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrImageAlreadyFreed, err)
+	}
+	if len(pixels.Data) != expectedSize {
+		t.Errorf("Expected pixels to outlive image")
+	}
+}
+
+func TestNewImageFromMemory(t *testing.T) {
+	initVips(t)
+
+	data := []byte{
+		255, 0, 0, 0, 255, 0,
+		0, 0, 255, 255, 255, 255,
+	}
+
+	val, err := imgvips.NewImageFromMemory(data, 2, 2, 3, imgvips.BandFormatUchar)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer val.Free()
+
+	// Source buffer can be modified after call
+	data[0] = 0
+
+	img, ok := val.Image()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+	if img.Width() != 2 || img.Height() != 2 || img.Bands() != 3 {
+		t.Fatalf("Expected image 2x2x3, got %dx%dx%d", img.Width(), img.Height(), img.Bands())
+	}
+
+	pixels, err := img.WriteToMemory()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	expected := []byte{
+		255, 0, 0, 0, 255, 0,
+		0, 0, 255, 255, 255, 255,
+	}
+	if !bytes.Equal(pixels.Data, expected) {
+		t.Fatalf("Expected pixels %v, got %v", expected, pixels.Data)
+	}
+}
+
+func TestNewImageFromMemory_Invalid(t *testing.T) {
+	initVips(t)
+
+	if _, err := imgvips.NewImageFromMemory(make([]byte, 4), 0, 2, 1, imgvips.BandFormatUchar); err != imgvips.ErrInvalidGeometry {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrInvalidGeometry, err)
+	}
+	if _, err := imgvips.NewImageFromMemory(make([]byte, 4), 2, 2, 1, imgvips.BandFormatNotSet); err != imgvips.ErrInvalidGeometry {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrInvalidGeometry, err)
+	}
+	if _, err := imgvips.NewImageFromMemory(make([]byte, 4), 2, 2, 1, imgvips.BandFormatUshort); err != imgvips.ErrInvalidPixelsSize {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrInvalidPixelsSize, err)
+	}
+	if _, err := imgvips.NewImageFromMemory(nil, 2, 2, 1, imgvips.BandFormatUchar); err != imgvips.ErrInvalidPixelsSize {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrInvalidPixelsSize, err)
+	}
+	if huge := int(^uint(0) >> 1); huge > math.MaxInt32 {
+		if _, err := imgvips.NewImageFromMemory(make([]byte, 4), huge, 1, 1, imgvips.BandFormatUchar); err != imgvips.ErrInvalidGeometry {
+			t.Errorf("Expected error %v, got %v", imgvips.ErrInvalidGeometry, err)
+		}
+	}
+	// Product of dimensions overflows int64
+	if _, err := imgvips.NewImageFromMemory(make([]byte, 4), math.MaxInt32, math.MaxInt32, math.MaxInt32, imgvips.BandFormatDouble); err != imgvips.ErrInvalidPixelsSize {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrInvalidPixelsSize, err)
+	}
+}
@@ -0,0 +1,107 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+
+static int imgvips_region_fetch(VipsImage *image, int left, int top, int width, int height, void *buf) {
+	VipsRegion *region = vips_region_new(image);
+	if (region == NULL) {
+		return -1;
+	}
+
+	VipsRect rect = {left, top, width, height};
+	if (vips_region_prepare(region, &rect)) {
+		g_object_unref(region);
+
+		return -1;
+	}
+
+	size_t line = VIPS_IMAGE_SIZEOF_PEL(image) * width;
+	for (int y = 0; y < height; y++) {
+		memcpy((char *) buf + line * y, VIPS_REGION_ADDR(region, left, top + y), line);
+	}
+	g_object_unref(region);
+
+	return 0;
+}
+
+static int imgvips_getpoint(VipsImage *image, double **vector, int *n, int x, int y) {
+	return vips_getpoint(image, vector, n, x, y, NULL);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+var (
+	// ErrOutOfBounds returns when requested area is out of image bounds
+	ErrOutOfBounds = errors.New("area out of image bounds")
+)
+
+// Region computes only pixels of provided area and return its copy in Go memory.
+//
+// libvips evaluates only the part of pipeline needed for this area,
+// so it can be used for sampling or scanning tiles of very large images.
+func (i *Image) Region(x, y, width, height int) (Pixels, error) {
+	if i.val.wasFreed() {
+		return Pixels{}, ErrImageAlreadyFreed
+	}
+	if width <= 0 || height <= 0 {
+		return Pixels{}, ErrInvalidGeometry
+	}
+	if x < 0 || y < 0 || x+width > i.Width() || y+height > i.Height() {
+		return Pixels{}, ErrOutOfBounds
+	}
+
+	pixels := Pixels{
+		Width:  width,
+		Height: height,
+		Bands:  i.Bands(),
+		Format: i.Format(),
+	}
+	size := width * height * pixels.Bands * pixels.Format.Size()
+	// Unknown band format or image without bands
+	if size <= 0 {
+		return Pixels{}, ErrInvalidGeometry
+	}
+	pixels.Data = make([]byte, size)
+
+	if C.imgvips_region_fetch(i.image, C.int(x), C.int(y), C.int(width), C.int(height), unsafe.Pointer(&pixels.Data[0])) != 0 {
+		return Pixels{}, vipsError()
+	}
+
+	return pixels, nil
+}
+
+// GetPoint return values of all bands of pixel at x, y.
+//
+// Complex bands are returned as pair of values.
+func (i *Image) GetPoint(x, y int) ([]float64, error) {
+	if i.val.wasFreed() {
+		return nil, ErrImageAlreadyFreed
+	}
+	if x < 0 || y < 0 || x >= i.Width() || y >= i.Height() {
+		return nil, ErrOutOfBounds
+	}
+
+	var (
+		vector *C.double
+		n      C.int
+	)
+	if C.imgvips_getpoint(i.image, &vector, &n, C.int(x), C.int(y)) != 0 {
+		return nil, vipsError()
+	}
+	defer C.g_free(C.gpointer(vector))
+
+	values := make([]float64, int(n))
+	for idx, value := range (*[1 << 20]C.double)(unsafe.Pointer(vector))[:n:n] {
+		values[idx] = float64(value)
+	}
+
+	return values, nil
+}
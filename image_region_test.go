@@ -0,0 +1,80 @@
+package imgvips_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestImage_Region(t *testing.T) {
+	initVips(t)
+
+	data := []byte{
+		0, 1, 2, 3,
+		4, 5, 6, 7,
+		8, 9, 10, 11,
+	}
+	val, err := imgvips.NewImageFromMemory(data, 4, 3, 1, imgvips.BandFormatUchar)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer val.Free()
+
+	img, ok := val.Image()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+
+	pixels, err := img.Region(1, 1, 2, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if pixels.Width != 2 || pixels.Height != 2 || pixels.Bands != 1 {
+		t.Fatalf("Expected region 2x2x1, got %dx%dx%d", pixels.Width, pixels.Height, pixels.Bands)
+	}
+	if !bytes.Equal(pixels.Data, []byte{5, 6, 9, 10}) {
+		t.Fatalf("Expected pixels %v, got %v", []byte{5, 6, 9, 10}, pixels.Data)
+	}
+
+	if _, err := img.Region(3, 0, 2, 2); err != imgvips.ErrOutOfBounds {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrOutOfBounds, err)
+	}
+	if _, err := img.Region(0, 0, 0, 2); err != imgvips.ErrInvalidGeometry {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrInvalidGeometry, err)
+	}
+}
+
+func TestImage_GetPoint(t *testing.T) {
+	initVips(t)
+
+	val, err := imgvips.NewImageFromMemory([]byte{1, 2, 3, 4, 5, 6}, 2, 1, 3, imgvips.BandFormatUchar)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	img, ok := val.Image()
+	if !ok {
+		t.Fatal("Expected to be ok")
+	}
+
+	point, err := img.GetPoint(1, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(point) != 3 || point[0] != 4 || point[1] != 5 || point[2] != 6 {
+		t.Fatalf("Expected point %v, got %v", []float64{4, 5, 6}, point)
+	}
+
+	if _, err := img.GetPoint(2, 0); err != imgvips.ErrOutOfBounds {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrOutOfBounds, err)
+	}
+
+	val.Free()
+	if _, err := img.GetPoint(0, 0); err != imgvips.ErrImageAlreadyFreed {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrImageAlreadyFreed, err)
+	}
+	if _, err := img.Region(0, 0, 1, 1); err != imgvips.ErrImageAlreadyFreed {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrImageAlreadyFreed, err)
+	}
+}
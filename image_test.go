@@ -28,6 +28,13 @@ func TestImage_Sizes(t *testing.T) {
 		t.Errorf("Expected height to by %d, got %d", 100, img.Height())
 	}
 
+	if img.Bands() != 1 {
+		t.Errorf("Expected bands to by %d, got %d", 1, img.Bands())
+	}
+	if img.Format() != imgvips.BandFormatFloat {
+		t.Errorf("Expected format to by %d, got %d", imgvips.BandFormatFloat, img.Format())
+	}
+
 	val.Free()
 
 	if img.Width() != 0 {
@@ -36,4 +43,10 @@ func TestImage_Sizes(t *testing.T) {
 	if img.Height() != 0 {
 		t.Errorf("Expected height to by %d, got %d", 0, img.Height())
 	}
+	if img.Bands() != 0 {
+		t.Errorf("Expected bands to by %d, got %d", 0, img.Bands())
+	}
+	if img.Format() != imgvips.BandFormatNotSet {
+		t.Errorf("Expected format to by %d, got %d", imgvips.BandFormatNotSet, img.Format())
+	}
 }
@@ -0,0 +1,270 @@
+/*
+Package imageformat registers libvips as decoder for image.Decode and image.DecodeConfig.
+
+Import it for side effect to read formats, which are not supported by standard library:
+
+	import _ "github.com/Arimeka/imgvips/imageformat"
+
+Registered formats are webp, heif (HEIC and AVIF), tiff, jxl, svg and pdf (first page).
+Format is available only if linked libvips was built with its support.
+libvips must be initialized with imgvips.Initialize before decoding.
+Limits set by imgvips.SetLimits are checked before pixels are decoded.
+*/
+package imageformat
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+
+	"github.com/Arimeka/imgvips"
+)
+
+var (
+	// ErrUnknownFormat returns when libvips don't known how to load data
+	ErrUnknownFormat = imgvips.ErrUnknownFormat
+)
+
+// svgSniffLen is size of data prefix, where svg root element is looked for in XML documents
+const svgSniffLen = 1024
+
+func init() {
+	formats := []struct {
+		name  string
+		magic []string
+	}{
+		{"webp", []string{"RIFF????WEBPVP8"}},
+		{"heif", []string{
+			"????ftypheic", "????ftypheix", "????ftyphevc", "????ftypheim", "????ftypheis",
+			"????ftypmif1", "????ftypmsf1", "????ftypavif", "????ftypavis",
+		}},
+		{"tiff", []string{"II*\x00", "MM\x00*"}},
+		{"jxl", []string{"\xff\x0a", "\x00\x00\x00\x0cJXL \x0d\x0a\x87\x0a"}},
+		{"svg", []string{"<svg"}},
+		{"pdf", []string{"%PDF-"}},
+	}
+
+	for _, format := range formats {
+		for _, magic := range format.magic {
+			image.RegisterFormat(format.name, magic, Decode, DecodeConfig)
+		}
+	}
+
+	// XML prolog can start any document, so data is decoded only if svg element is in sniffed prefix
+	image.RegisterFormat("svg", "<?xml", decodeSVG, decodeSVGConfig)
+}
+
+// Decode decodes image with libvips loaders.
+//
+// Image is converted to sRGB (or B_W for grey images) and returned as described in imgvips.Image.ToGoImage.
+func Decode(r io.Reader) (image.Image, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(data)
+}
+
+// DecodeConfig returns image dimensions and color model without decoding pixels.
+//
+// Color model is the one of image returned by Decode.
+func DecodeConfig(r io.Reader) (image.Config, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	return decodeConfig(data)
+}
+
+func decodeSVG(r io.Reader) (image.Image, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !isSVG(data) {
+		return nil, ErrUnknownFormat
+	}
+
+	return decode(data)
+}
+
+func decodeSVGConfig(r io.Reader) (image.Config, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+	if !isSVG(data) {
+		return image.Config{}, ErrUnknownFormat
+	}
+
+	return decodeConfig(data)
+}
+
+// isSVG reports whether svg element starts in sniffed prefix of XML document
+func isSVG(data []byte) bool {
+	if len(data) > svgSniffLen {
+		data = data[:svgSniffLen]
+	}
+
+	return bytes.Contains(data, []byte("<svg"))
+}
+
+func decode(data []byte) (image.Image, error) {
+	out, op, err := imgvips.LoadBuffer(data)
+	if err != nil {
+		return nil, err
+	}
+	defer op.Free()
+
+	img, free, err := goCompatible(out)
+	if err != nil {
+		return nil, err
+	}
+	defer free()
+
+	return img.ToGoImage()
+}
+
+func decodeConfig(data []byte) (image.Config, error) {
+	out, op, err := imgvips.LoadBuffer(data)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer op.Free()
+
+	// Conversion is lazy, so only header of converted image is built here
+	img, free, err := goCompatible(out)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer free()
+
+	return image.Config{
+		ColorModel: colorModel(img),
+		Width:      img.Width(),
+		Height:     img.Height(),
+	}, nil
+}
+
+// goCompatible converts image to colourspace and format, which can be used by image package.
+//
+// Pixels are not computed. On success returned func frees conversion operations and must be called after image is used.
+func goCompatible(in *imgvips.GValue) (*imgvips.Image, func(), error) {
+	var ops []*imgvips.Operation
+	free := func() {
+		for _, op := range ops {
+			op.Free()
+		}
+	}
+
+	img, ok := in.Image()
+	if !ok || img == nil {
+		return nil, nil, ErrUnknownFormat
+	}
+
+	if isGoCompatible(img) {
+		return img, free, nil
+	}
+
+	space := imgvips.InterpretationSRGB
+	if img.Bands() < 3 {
+		space = imgvips.InterpretationBW
+	}
+
+	op, err := imgvips.NewOperation("colourspace")
+	if err != nil {
+		return nil, nil, err
+	}
+	ops = append(ops, op)
+
+	out := imgvips.GNullVipsImage()
+	op.AddInput("in", in)
+	op.AddInput("space", imgvips.GInt(int(space)))
+	op.AddOutput("out", out)
+
+	if err := op.Exec(); err != nil {
+		free()
+		return nil, nil, err
+	}
+
+	img, ok = out.Image()
+	if !ok || img == nil {
+		free()
+		return nil, nil, ErrUnknownFormat
+	}
+	if img.Format() == imgvips.BandFormatUchar || img.Format() == imgvips.BandFormatUshort {
+		return img, free, nil
+	}
+
+	castOp, err := imgvips.NewOperation("cast")
+	if err != nil {
+		free()
+		return nil, nil, err
+	}
+	ops = append(ops, castOp)
+
+	castOut := imgvips.GNullVipsImage()
+	castOp.AddInput("in", out)
+	castOp.AddInput("format", imgvips.GInt(int(imgvips.BandFormatUchar)))
+	castOp.AddOutput("out", castOut)
+
+	if err := castOp.Exec(); err != nil {
+		free()
+		return nil, nil, err
+	}
+
+	img, ok = castOut.Image()
+	if !ok || img == nil {
+		free()
+		return nil, nil, ErrUnknownFormat
+	}
+
+	return img, free, nil
+}
+
+// isGoCompatible reports whether image pixels can be used by image package as is
+func isGoCompatible(img *imgvips.Image) bool {
+	switch img.Interpretation() {
+	case imgvips.InterpretationSRGB, imgvips.InterpretationRGB16:
+		if img.Bands() != 3 && img.Bands() != 4 {
+			return false
+		}
+	case imgvips.InterpretationBW, imgvips.InterpretationGrey16:
+		if img.Bands() != 1 && img.Bands() != 2 {
+			return false
+		}
+	default:
+		return false
+	}
+
+	return img.Format() == imgvips.BandFormatUchar || img.Format() == imgvips.BandFormatUshort
+}
+
+func colorModel(img *imgvips.Image) color.Model {
+	deep := img.Format() == imgvips.BandFormatUshort
+
+	switch img.Bands() {
+	case 1:
+		if deep {
+			return color.Gray16Model
+		}
+
+		return color.GrayModel
+	case 3:
+		if deep {
+			return color.RGBA64Model
+		}
+
+		return color.RGBAModel
+	default:
+		if deep {
+			return color.NRGBA64Model
+		}
+
+		return color.NRGBAModel
+	}
+}
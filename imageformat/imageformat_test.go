@@ -0,0 +1,70 @@
+package imageformat_test
+
+import (
+	"bytes"
+	"image"
+	"io/ioutil"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+	"github.com/Arimeka/imgvips/imageformat"
+)
+
+func initVips(t testing.TB) {
+	err := imgvips.Initialize(imgvips.VipsCacheSetMaxMem(0), imgvips.VipsCacheSetMax(0),
+		imgvips.VipsVectorSetEnables(false))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	initVips(t)
+
+	data, err := ioutil.ReadFile("../tests/fixtures/small.webp")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if format != "webp" {
+		t.Errorf("Expected format %s, got %s", "webp", format)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		t.Fatalf("Expected positive size, got %dx%d", config.Width, config.Height)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if format != "webp" {
+		t.Errorf("Expected format %s, got %s", "webp", format)
+	}
+	if img.Bounds().Dx() != config.Width || img.Bounds().Dy() != config.Height {
+		t.Errorf("Expected size %dx%d, got %dx%d", config.Width, config.Height, img.Bounds().Dx(), img.Bounds().Dy())
+	}
+	if img.ColorModel() != config.ColorModel {
+		t.Errorf("Expected color model %v, got %v", config.ColorModel, img.ColorModel())
+	}
+}
+
+func TestDecode_Unknown(t *testing.T) {
+	initVips(t)
+
+	if _, err := imageformat.Decode(bytes.NewReader([]byte("foobar"))); err != imageformat.ErrUnknownFormat {
+		t.Fatalf("Expected error %v, got %v", imageformat.ErrUnknownFormat, err)
+	}
+}
+
+func TestDecode_XMLNotSVG(t *testing.T) {
+	initVips(t)
+
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?><feed></feed>`)
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != imageformat.ErrUnknownFormat {
+		t.Fatalf("Expected error %v, got %v", imageformat.ErrUnknownFormat, err)
+	}
+}
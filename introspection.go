@@ -0,0 +1,222 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+
+static GSList *imgvips_operation_arguments(VipsOperation *op) {
+	return VIPS_OBJECT_GET_CLASS(op)->argument_table_traverse;
+}
+
+static GParamSpec *imgvips_argument_pspec(GSList *p) {
+	return ((VipsArgument *) p->data)->pspec;
+}
+
+static GType imgvips_pspec_value_type(GParamSpec *pspec) {
+	return G_PARAM_SPEC_VALUE_TYPE(pspec);
+}
+
+static int imgvips_argument_flags(GSList *p) {
+	return ((VipsArgumentClass *) p->data)->flags;
+}
+
+static void imgvips_pspec_int_range(GParamSpec *pspec, int *min, int *max) {
+	gint64 lo, hi;
+
+	if (G_IS_PARAM_SPEC_INT64(pspec)) {
+		lo = G_PARAM_SPEC_INT64(pspec)->minimum;
+		hi = G_PARAM_SPEC_INT64(pspec)->maximum;
+	} else if (G_IS_PARAM_SPEC_UINT64(pspec)) {
+		lo = 0;
+		hi = G_PARAM_SPEC_UINT64(pspec)->maximum > G_MAXINT ? G_MAXINT : G_PARAM_SPEC_UINT64(pspec)->maximum;
+	} else {
+		*min = G_PARAM_SPEC_INT(pspec)->minimum;
+		*max = G_PARAM_SPEC_INT(pspec)->maximum;
+
+		return;
+	}
+
+	// Values are set as gint, so 64-bit ranges are clamped to it
+	*min = lo < G_MININT ? G_MININT : lo;
+	*max = hi > G_MAXINT ? G_MAXINT : hi;
+}
+
+static void imgvips_pspec_double_range(GParamSpec *pspec, double *min, double *max) {
+	*min = G_PARAM_SPEC_DOUBLE(pspec)->minimum;
+	*max = G_PARAM_SPEC_DOUBLE(pspec)->maximum;
+}
+
+static gboolean imgvips_enum_value(GType type, const char *nick, int *value) {
+	GEnumClass *class = g_type_class_ref(type);
+	GEnumValue *v = g_enum_get_value_by_nick(class, nick);
+	if (v == NULL) {
+		v = g_enum_get_value_by_name(class, nick);
+	}
+	if (v != NULL) {
+		*value = v->value;
+	}
+	g_type_class_unref(class);
+
+	return v != NULL;
+}
+
+static gboolean imgvips_enum_has(GType type, int value) {
+	GEnumClass *class = g_type_class_ref(type);
+	gboolean found = g_enum_get_value(class, value) != NULL;
+	g_type_class_unref(class);
+
+	return found;
+}
+
+static const char *imgvips_enum_nick(GType type, guint i) {
+	GEnumClass *class = g_type_class_ref(type);
+	const char *nick = i < class->n_values ? class->values[i].value_nick : NULL;
+	g_type_class_unref(class);
+
+	return nick;
+}
+
+static gboolean imgvips_flags_value(GType type, const char *nick, guint *value) {
+	GFlagsClass *class = g_type_class_ref(type);
+	GFlagsValue *v = g_flags_get_value_by_nick(class, nick);
+	if (v == NULL) {
+		v = g_flags_get_value_by_name(class, nick);
+	}
+	if (v != NULL) {
+		*value = v->value;
+	}
+	g_type_class_unref(class);
+
+	return v != NULL;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// argumentInfo describes operation argument declared by libvips
+type argumentInfo struct {
+	name  string
+	gType C.GType
+	flags C.int
+	pspec *C.GParamSpec
+}
+
+func (a argumentInfo) is(flag C.int) bool {
+	return a.flags&flag != 0
+}
+
+func (a argumentInfo) isImage() bool {
+	return a.gType == C.vips_image_get_type()
+}
+
+// fundamental return fundamental type of argument, e.g. G_TYPE_ENUM for VipsKernel
+func (a argumentInfo) fundamental() C.GType {
+	return C.g_type_fundamental(a.gType)
+}
+
+// intRange return range of int argument, int64 and uint64 ranges are clamped to int32
+func (a argumentInfo) intRange() (min, max int) {
+	var cMin, cMax C.int
+	C.imgvips_pspec_int_range(a.pspec, &cMin, &cMax)
+
+	return int(cMin), int(cMax)
+}
+
+func (a argumentInfo) doubleRange() (min, max float64) {
+	var cMin, cMax C.double
+	C.imgvips_pspec_double_range(a.pspec, &cMin, &cMax)
+
+	return float64(cMin), float64(cMax)
+}
+
+// enumValue return value of enum argument by nick or name, e.g. lanczos3 or VIPS_KERNEL_LANCZOS3
+func (a argumentInfo) enumValue(nick string) (int, bool) {
+	cNick := C.CString(nick)
+	defer C.free(unsafe.Pointer(cNick))
+
+	var value C.int
+	if C.imgvips_enum_value(a.gType, cNick, &value) == 0 {
+		return 0, false
+	}
+
+	return int(value), true
+}
+
+func (a argumentInfo) enumHas(value int) bool {
+	return C.imgvips_enum_has(a.gType, C.int(value)) != 0
+}
+
+// enumNicks return all nicks of enum argument
+func (a argumentInfo) enumNicks() []string {
+	var nicks []string
+	for i := C.guint(0); ; i++ {
+		nick := C.imgvips_enum_nick(a.gType, i)
+		if nick == nil {
+			return nicks
+		}
+		nicks = append(nicks, C.GoString(nick))
+	}
+}
+
+// flagsValue return value of flags argument by nick or name
+func (a argumentInfo) flagsValue(nick string) (int, bool) {
+	cNick := C.CString(nick)
+	defer C.free(unsafe.Pointer(cNick))
+
+	var value C.guint
+	if C.imgvips_flags_value(a.gType, cNick, &value) == 0 {
+		return 0, false
+	}
+
+	return int(value), true
+}
+
+// operationArguments return not deprecated arguments of operation in libvips order
+func operationArguments(op *C.VipsOperation) []argumentInfo {
+	var args []argumentInfo
+
+	for p := C.imgvips_operation_arguments(op); p != nil; p = p.next {
+		arg := argumentInfo{
+			pspec: C.imgvips_argument_pspec(p),
+			flags: C.imgvips_argument_flags(p),
+		}
+		if arg.is(C.VIPS_ARGUMENT_DEPRECATED) {
+			continue
+		}
+		arg.name = C.GoString(C.g_param_spec_get_name(arg.pspec))
+		arg.gType = C.imgvips_pspec_value_type(arg.pspec)
+
+		args = append(args, arg)
+	}
+
+	return args
+}
+
+// mainImageArguments return names of first required input and output images, e.g. in and out.
+// Name is empty if operation has no such argument.
+func (op *Operation) mainImageArguments() (input, output string) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	if op.operation == nil {
+		return "", ""
+	}
+
+	for _, arg := range operationArguments(op.operation) {
+		if !arg.isImage() || !arg.is(C.VIPS_ARGUMENT_REQUIRED) {
+			continue
+		}
+		if input == "" && arg.is(C.VIPS_ARGUMENT_INPUT) {
+			input = arg.name
+		}
+		if output == "" && arg.is(C.VIPS_ARGUMENT_OUTPUT) {
+			output = arg.name
+		}
+	}
+
+	return input, output
+}
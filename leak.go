@@ -0,0 +1,98 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+
+extern void imgvipsLeakObject(char *type, char *nickname);
+
+static void *imgvips_leak_object(VipsObject *object, void *a, void *b) {
+	imgvipsLeakObject((char *) G_OBJECT_TYPE_NAME(object), (char *) VIPS_OBJECT_GET_CLASS(object)->nickname);
+
+	return NULL;
+}
+
+static void imgvips_leak_objects(void) {
+	vips_object_map((VipsSListMap2Fn) imgvips_leak_object, NULL, NULL);
+}
+*/
+import "C"
+
+import (
+	"sync"
+)
+
+var leakCollector = &leakObjects{}
+
+type leakObjects struct {
+	objects []LeakedObject
+	mu      sync.Mutex
+}
+
+// LeakedObject is VipsObject alive at the moment of report
+type LeakedObject struct {
+	// Type is GType name, e.g. VipsImage
+	Type string
+	// Nickname is libvips nickname of object class, e.g. image or webpload_buffer
+	Nickname string
+}
+
+// Report contains libvips objects and tracked resources alive at the moment of report
+type Report struct {
+	Objects []LeakedObject
+	// Allocs is number of tracked allocations
+	Allocs int
+	// Mem is tracked memory in bytes
+	Mem int64
+	// Files is number of tracked open files
+	Files int
+}
+
+// Images return number of alive VipsImage objects
+func (r Report) Images() int {
+	count := 0
+	for _, object := range r.Objects {
+		if object.Type == "VipsImage" {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Empty return true if report has no alive objects, allocations and files
+func (r Report) Empty() bool {
+	return len(r.Objects) == 0 && r.Allocs == 0 && r.Mem == 0 && r.Files == 0
+}
+
+// LeakReport return all alive VipsObjects and tracked resources.
+//
+// Operations in libvips cache are alive too, so drop cache with CacheDropAll() or turn it off before call.
+// After Shutdown(), function return ErrNotInitialized.
+func LeakReport() (Report, error) {
+	if vipsState.isShutdown() {
+		return Report{}, ErrNotInitialized
+	}
+
+	leakCollector.mu.Lock()
+	defer leakCollector.mu.Unlock()
+
+	leakCollector.objects = nil
+	C.imgvips_leak_objects()
+
+	report := Report{
+		Objects: leakCollector.objects,
+		Allocs:  int(C.vips_tracked_get_allocs()),
+		Mem:     int64(C.vips_tracked_get_mem()),
+		Files:   int(C.vips_tracked_get_files()),
+	}
+	leakCollector.objects = nil
+
+	return report, nil
+}
+
+// add is called from libvips while LeakReport holds lock
+func (l *leakObjects) add(object LeakedObject) {
+	l.objects = append(l.objects, object)
+}
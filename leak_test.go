@@ -0,0 +1,42 @@
+package imgvips_test
+
+import (
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestLeakReport(t *testing.T) {
+	initVips(t)
+	imgvips.CacheDropAll()
+
+	before, err := imgvips.LeakReport()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	val, op := generateImage(t)
+
+	report, err := imgvips.LeakReport()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if report.Images() <= before.Images() {
+		t.Errorf("Expected more than %d images, got %d", before.Images(), report.Images())
+	}
+
+	val.Free()
+	op.Free()
+	imgvips.CacheDropAll()
+
+	after, err := imgvips.LeakReport()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if after.Images() != before.Images() {
+		t.Errorf("Expected %d images, got %d", before.Images(), after.Images())
+	}
+	if len(after.Objects) != len(before.Objects) {
+		t.Errorf("Expected %d objects, got %d: %+v", len(before.Objects), len(after.Objects), after.Objects)
+	}
+}
@@ -0,0 +1,150 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+var (
+	// ErrLimitExceeded returns when image or operation exceeds Limits.
+	// Returned errors wrap it with exceeded limit details, use errors.Is for check.
+	ErrLimitExceeded = errors.New("limit exceeded")
+)
+
+var defaultLimits = &limitsHolder{}
+
+type limitsHolder struct {
+	limits Limits
+	mu     sync.RWMutex
+}
+
+func (h *limitsHolder) get() Limits {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.limits
+}
+
+func (h *limitsHolder) set(limits Limits) {
+	h.mu.Lock()
+	h.limits = limits
+	h.mu.Unlock()
+}
+
+// Limits protects from decompression bombs and heavy operations on untrusted input.
+//
+// Zero value of any field means no limit.
+// Memory is not limited directly: operations are built lazily and pixels are computed later,
+// so limit image dimensions to bound memory used by computation.
+type Limits struct {
+	// MaxPixels is maximum width*height of output images
+	MaxPixels int64
+	// MaxWidth is maximum width of output images
+	MaxWidth int
+	// MaxHeight is maximum height of output images
+	MaxHeight int
+	// MaxPages is maximum number of pages in loaded image file
+	MaxPages int
+	// MaxInputBytes is maximum size of VipsBlob inputs and files opened by LoadFile
+	MaxInputBytes int64
+}
+
+// SetLimits set limits for all operations created after call.
+//
+// Use *Operation.SetLimits() to override limits for single operation.
+func SetLimits(limits Limits) {
+	defaultLimits.set(limits)
+}
+
+// GetLimits return limits for new operations
+func GetLimits() Limits {
+	return defaultLimits.get()
+}
+
+func (l Limits) checkInputBytes(size int64) error {
+	if l.MaxInputBytes > 0 && size > l.MaxInputBytes {
+		return fmt.Errorf("%w: input size %d bytes, max %d", ErrLimitExceeded, size, l.MaxInputBytes)
+	}
+
+	return nil
+}
+
+// checkImage checks image header, it does not compute image pixels
+func (l Limits) checkImage(img *Image) error {
+	width, height := img.Width(), img.Height()
+
+	if l.MaxWidth > 0 && width > l.MaxWidth {
+		return fmt.Errorf("%w: width %d, max %d", ErrLimitExceeded, width, l.MaxWidth)
+	}
+	if l.MaxHeight > 0 && height > l.MaxHeight {
+		return fmt.Errorf("%w: height %d, max %d", ErrLimitExceeded, height, l.MaxHeight)
+	}
+	if pixels := int64(width) * int64(height); l.MaxPixels > 0 && pixels > l.MaxPixels {
+		return fmt.Errorf("%w: %d pixels, max %d", ErrLimitExceeded, pixels, l.MaxPixels)
+	}
+	if pages := img.Pages(); l.MaxPages > 0 && pages > l.MaxPages {
+		return fmt.Errorf("%w: %d pages, max %d", ErrLimitExceeded, pages, l.MaxPages)
+	}
+
+	return nil
+}
+
+// checkInputs checks size of VipsBlob inputs
+func (op *Operation) checkInputs() error {
+	if op.limits.MaxInputBytes <= 0 {
+		return nil
+	}
+
+	for _, arg := range op.inputs {
+		val, ok := arg.value().(*GValue)
+		if !ok {
+			continue
+		}
+		if err := op.limits.checkInputBytes(val.blobSize()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkOutputs checks headers of output images before they will be set to output arguments
+func (op *Operation) checkOutputs() error {
+	if op.limits.MaxWidth <= 0 && op.limits.MaxHeight <= 0 && op.limits.MaxPixels <= 0 && op.limits.MaxPages <= 0 {
+		return nil
+	}
+
+	for _, arg := range op.outputs {
+		val, ok := arg.value().(*GValue)
+		if !ok || !val.isImage() {
+			continue
+		}
+
+		var gValue C.GValue
+		out := &GValue{gType: C.vips_image_get_type(), gValue: &gValue}
+		C.g_value_init(out.gValue, out.gType)
+		C.g_object_get_property((*C.GObject)(unsafe.Pointer(op.operation)), arg.name(), out.gValue)
+
+		var err error
+		if img, ok := out.Image(); ok && img != nil {
+			err = op.limits.checkImage(img)
+		}
+		// Release only reference taken by g_object_get_property
+		C.g_value_unset(out.gValue)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
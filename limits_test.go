@@ -0,0 +1,86 @@
+package imgvips_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestSetLimits(t *testing.T) {
+	initVips(t)
+
+	imgvips.SetLimits(imgvips.Limits{MaxWidth: 10})
+	defer imgvips.SetLimits(imgvips.Limits{})
+
+	if imgvips.GetLimits().MaxWidth != 10 {
+		t.Fatalf("Expected max width %d, got %d", 10, imgvips.GetLimits().MaxWidth)
+	}
+
+	_, _, err := imgvips.LoadFile("./tests/fixtures/small.webp")
+	if !errors.Is(err, imgvips.ErrLimitExceeded) {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrLimitExceeded, err)
+	}
+
+	_, err = imgvips.ProbeFile("./tests/fixtures/small.webp")
+	if !errors.Is(err, imgvips.ErrLimitExceeded) {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrLimitExceeded, err)
+	}
+
+	imgvips.SetLimits(imgvips.Limits{MaxInputBytes: 10})
+
+	_, _, err = imgvips.LoadFile("./tests/fixtures/small.webp")
+	if !errors.Is(err, imgvips.ErrLimitExceeded) {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrLimitExceeded, err)
+	}
+}
+
+func TestOperation_SetLimits(t *testing.T) {
+	initVips(t)
+
+	data, err := ioutil.ReadFile("./tests/fixtures/small.webp")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		limits imgvips.Limits
+		err    error
+	}{
+		{"no limits", imgvips.Limits{}, nil},
+		{"input bytes", imgvips.Limits{MaxInputBytes: int64(len(data) - 1)}, imgvips.ErrLimitExceeded},
+		{"pixels", imgvips.Limits{MaxPixels: 1}, imgvips.ErrLimitExceeded},
+		{"height", imgvips.Limits{MaxHeight: 1}, imgvips.ErrLimitExceeded},
+		{"pages", imgvips.Limits{MaxPages: 1}, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			op, err := imgvips.NewOperation("webpload_buffer")
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			defer op.Free()
+
+			out := imgvips.GNullVipsImage()
+			op.SetLimits(c.limits)
+			op.AddInput("buffer", imgvips.GVipsBlob(data))
+			op.AddOutput("out", out)
+
+			err = op.Exec()
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+
+			img, ok := out.Image()
+			if !ok {
+				t.Fatal("Expected to be ok")
+			}
+			if c.err != nil && img != nil {
+				t.Fatal("Expected output to not be updated")
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+*/
+import "C"
+
+import (
+	"os"
+	"unsafe"
+)
+
+// LoadBuffer loads image from bytes array with loader detected by libvips.
+//
+// Only header is read, pixels are computed when image is used.
+// You must protect bytes array from GC and modification while using the image.
+// Returned operation owns image, call *Operation.Free() after image is no longer needed.
+func LoadBuffer(data []byte) (*GValue, *Operation, error) {
+	opName, err := findLoadBuffer(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return load(opName, "buffer", GVipsBlob(data))
+}
+
+// findLoadBuffer return name of loader for bytes array
+func findLoadBuffer(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", ErrUnknownFormat
+	}
+
+	cOpName := C.vips_foreign_find_load_buffer(unsafe.Pointer(&data[0]), C.size_t(len(data)))
+	if cOpName == nil {
+		VipsErrorFree()
+
+		return "", ErrUnknownFormat
+	}
+
+	return C.GoString(cOpName), nil
+}
+
+// LoadFile loads image from file with loader detected by libvips.
+//
+// Only header is read, pixels are computed when image is used.
+// If file can't be opened, *os.PathError is returned.
+// Returned operation owns image, call *Operation.Free() after image is no longer needed.
+func LoadFile(filename string) (*GValue, *Operation, error) {
+	// Missing or unreadable file is reported as is, not as ErrUnknownFormat
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	stat, err := file.Stat()
+	file.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := GetLimits().checkInputBytes(stat.Size()); err != nil {
+		return nil, nil, err
+	}
+
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+
+	cOpName := C.vips_foreign_find_load(cFilename)
+	if cOpName == nil {
+		VipsErrorFree()
+
+		return nil, nil, ErrUnknownFormat
+	}
+
+	return load(C.GoString(cOpName), "filename", GString(filename))
+}
+
+func load(opName, argName string, arg *GValue) (*GValue, *Operation, error) {
+	op, err := NewOperation(opName)
+	if err != nil {
+		arg.Free()
+
+		return nil, nil, err
+	}
+
+	out := GNullVipsImage()
+	op.AddInput(argName, arg)
+	op.AddOutput("out", out)
+
+	if err := op.Exec(); err != nil {
+		op.Free()
+
+		return nil, nil, err
+	}
+
+	return out, op, nil
+}
@@ -0,0 +1,51 @@
+package imgvips_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestLoadBuffer(t *testing.T) {
+	initVips(t)
+
+	data, err := ioutil.ReadFile("./tests/fixtures/small.webp")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	out, op, err := imgvips.LoadBuffer(data)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer op.Free()
+
+	img, ok := out.Image()
+	if !ok || img == nil {
+		t.Fatal("Expected *C.VipsImage in out")
+	}
+
+	if _, _, err := imgvips.LoadBuffer([]byte("foobar")); err != imgvips.ErrUnknownFormat {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrUnknownFormat, err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	initVips(t)
+
+	out, op, err := imgvips.LoadFile("./tests/fixtures/small.webp")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer op.Free()
+
+	img, ok := out.Image()
+	if !ok || img == nil {
+		t.Fatal("Expected *C.VipsImage in out")
+	}
+
+	if _, _, err := imgvips.LoadFile("./tests/fixtures/not_exists.webp"); err == nil {
+		t.Fatal("Expected to return error, got nil")
+	}
+}
@@ -0,0 +1,195 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+
+#if VIPS_MAJOR_VERSION > 8 || (VIPS_MAJOR_VERSION == 8 && VIPS_MINOR_VERSION >= 13)
+static void imgvips_operation_block_set(const char *name, gboolean state) {
+	vips_operation_block_set(name, state);
+}
+
+static gboolean imgvips_operation_is_blocked(GType type) {
+	VipsOperationClass *class = VIPS_OPERATION_CLASS(g_type_class_ref(type));
+	gboolean blocked = (class->flags & VIPS_OPERATION_BLOCKED) != 0;
+	g_type_class_unref(class);
+
+	return blocked;
+}
+
+static void *imgvips_loader_type_collect(GType type, void *a) {
+	GSList **list = a;
+	if (!G_TYPE_IS_ABSTRACT(type)) {
+		*list = g_slist_prepend(*list, GSIZE_TO_POINTER(type));
+	}
+
+	return NULL;
+}
+
+static GSList *imgvips_loader_types(void) {
+	GSList *list = NULL;
+	vips_type_map_all(vips_foreign_load_get_type(), imgvips_loader_type_collect, &list);
+
+	return list;
+}
+#else
+static void imgvips_operation_block_set(const char *name, gboolean state) {
+}
+
+static gboolean imgvips_operation_is_blocked(GType type) {
+	return FALSE;
+}
+
+static GSList *imgvips_loader_types(void) {
+	return NULL;
+}
+#endif
+
+static GType imgvips_loader_type(GSList *p) {
+	return GPOINTER_TO_SIZE(p->data);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrLoaderNotAllowed returns when loader is forbidden by loaders policy
+	ErrLoaderNotAllowed = errors.New("loader not allowed")
+)
+
+// UntrustedLoaders is preset of loaders, which are reasonably safe for user uploads.
+//
+// Use it with SetAllowedLoaders().
+var UntrustedLoaders = []string{"jpegload", "pngload", "webpload", "gifload", "heifload"}
+
+// loaderVariants are suffixes of loader nicknames, which load from buffer or source
+var loaderVariants = []string{"_buffer", "_source"}
+
+var loadersPolicy = &loaderPolicy{}
+
+type loaderPolicy struct {
+	allowed map[string]bool
+	blocked map[string]bool
+	// changed is loaders, which were blocked in libvips by policy
+	changed []string
+	mu      sync.RWMutex
+}
+
+// SetAllowedLoaders allows to use only provided loaders, e.g. jpegload or pngload.
+//
+// Loader name allows also its _buffer and _source variants. Pass nil to allow all loaders.
+// NewOperation() and load helpers return ErrLoaderNotAllowed for other loaders.
+// With libvips 8.13+ other loaders are also blocked with vips_operation_block_set,
+// so libvips won't use them internally. With older versions operations, which load images internally,
+// e.g. thumbnail_buffer, are not covered, Thumbnail() checks loader before use instead.
+//
+// Must be called after Initialize().
+func SetAllowedLoaders(loaders []string) {
+	loadersPolicy.setAllowed(loaders)
+}
+
+// SetBlockedLoaders forbids to use provided loaders, e.g. magickload or pdfload.
+//
+// Loader name blocks also its _buffer and _source variants. Pass nil to unblock all loaders.
+// Blocked loaders are forbidden even if allowed by SetAllowedLoaders().
+//
+// Must be called after Initialize().
+func SetBlockedLoaders(loaders []string) {
+	loadersPolicy.setBlocked(loaders)
+}
+
+func (p *loaderPolicy) setAllowed(loaders []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.allowed = loadersSet(loaders)
+	p.apply()
+}
+
+func (p *loaderPolicy) setBlocked(loaders []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.blocked = loadersSet(loaders)
+	p.apply()
+}
+
+func (p *loaderPolicy) isAllowed(nickname string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.allows(nickname)
+}
+
+func (p *loaderPolicy) allows(nickname string) bool {
+	name := loaderBaseName(nickname)
+
+	if p.blocked[name] {
+		return false
+	}
+
+	return p.allowed == nil || p.allowed[name]
+}
+
+// apply blocks forbidden loaders in libvips, if supported.
+//
+// Only loaders forbidden by policy are changed: loaders blocked elsewhere, e.g. with VIPS_BLOCK_UNTRUSTED,
+// stay blocked after policy reset, allowed loaders are not unblocked.
+func (p *loaderPolicy) apply() {
+	for _, name := range p.changed {
+		C.imgvips_operation_block_set(cStringsCache.get(name), C.gboolean(0))
+	}
+	p.changed = nil
+
+	list := C.imgvips_loader_types()
+	if list == nil {
+		return
+	}
+	defer C.g_slist_free(list)
+
+	for l := list; l != nil; l = l.next {
+		gType := C.imgvips_loader_type(l)
+		nickname := C.vips_nickname_find(gType)
+		if nickname == nil || C.imgvips_operation_is_blocked(gType) != 0 {
+			continue
+		}
+
+		name := C.GoString(nickname)
+		if p.allows(name) {
+			continue
+		}
+
+		C.imgvips_operation_block_set(cStringsCache.get(name), C.gboolean(1))
+		p.changed = append(p.changed, name)
+	}
+}
+
+func loadersSet(loaders []string) map[string]bool {
+	if loaders == nil {
+		return nil
+	}
+
+	set := make(map[string]bool, len(loaders))
+	for _, name := range loaders {
+		set[loaderBaseName(name)] = true
+	}
+
+	return set
+}
+
+// loaderBaseName strips _buffer and _source suffixes from loader nickname
+func loaderBaseName(nickname string) string {
+	for _, variant := range loaderVariants {
+		if strings.HasSuffix(nickname, variant) {
+			return strings.TrimSuffix(nickname, variant)
+		}
+	}
+
+	return nickname
+}
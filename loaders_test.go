@@ -0,0 +1,66 @@
+package imgvips_test
+
+import (
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestSetAllowedLoaders(t *testing.T) {
+	initVips(t)
+
+	imgvips.SetAllowedLoaders([]string{"jpegload"})
+	defer imgvips.SetAllowedLoaders(nil)
+
+	op, err := imgvips.NewOperation("jpegload_buffer")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	op.Free()
+
+	if _, err := imgvips.NewOperation("webpload"); err != imgvips.ErrLoaderNotAllowed {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrLoaderNotAllowed, err)
+	}
+	if _, _, err := imgvips.LoadFile("./tests/fixtures/small.webp"); err == nil {
+		t.Fatal("Expected to return error, got nil")
+	}
+
+	// Not loaders are not affected
+	op, err = imgvips.NewOperation("resize")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	op.Free()
+
+	imgvips.SetAllowedLoaders(imgvips.UntrustedLoaders)
+
+	out, loadOp, err := imgvips.LoadFile("./tests/fixtures/small.webp")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer loadOp.Free()
+
+	if img, ok := out.Image(); !ok || img == nil {
+		t.Fatal("Expected *C.VipsImage in out")
+	}
+}
+
+func TestSetBlockedLoaders(t *testing.T) {
+	initVips(t)
+
+	imgvips.SetAllowedLoaders(imgvips.UntrustedLoaders)
+	defer imgvips.SetAllowedLoaders(nil)
+
+	imgvips.SetBlockedLoaders([]string{"webpload_buffer"})
+	defer imgvips.SetBlockedLoaders(nil)
+
+	if _, err := imgvips.NewOperation("webpload"); err != imgvips.ErrLoaderNotAllowed {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrLoaderNotAllowed, err)
+	}
+
+	op, err := imgvips.NewOperation("pngload")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	op.Free()
+}
@@ -0,0 +1,185 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+
+extern void imgvipsLog(char *domain, int level, char *message);
+
+static void imgvips_log_handler(const gchar *domain, GLogLevelFlags level, const gchar *message, gpointer user) {
+	imgvipsLog((char *) domain, (int) level, (char *) message);
+}
+
+static void imgvips_log_set_handler(void) {
+	g_log_set_default_handler(imgvips_log_handler, NULL);
+}
+
+static void imgvips_log_default(const char *domain, int level, const char *message) {
+	g_log_default_handler(domain, (GLogLevelFlags) level, message, NULL);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+var (
+	// ErrWarning returns from *Operation.Exec(), if warnings are escalated to errors.
+	// Returned errors wrap it with log domain and message, use errors.Is for check.
+	ErrWarning = errors.New("vips warning")
+)
+
+// Level is GLib log level
+type Level int
+
+// Available log levels
+const (
+	LevelError    Level = C.G_LOG_LEVEL_ERROR
+	LevelCritical Level = C.G_LOG_LEVEL_CRITICAL
+	LevelWarning  Level = C.G_LOG_LEVEL_WARNING
+	LevelMessage  Level = C.G_LOG_LEVEL_MESSAGE
+	LevelInfo     Level = C.G_LOG_LEVEL_INFO
+	LevelDebug    Level = C.G_LOG_LEVEL_DEBUG
+)
+
+// String return level name
+func (l Level) String() string {
+	switch l {
+	case LevelError:
+		return "error"
+	case LevelCritical:
+		return "critical"
+	case LevelWarning:
+		return "warning"
+	case LevelMessage:
+		return "message"
+	case LevelInfo:
+		return "info"
+	case LevelDebug:
+		return "debug"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+// LogFunc receives GLib and libvips log messages, e.g. domain VIPS or GLib-GObject
+type LogFunc func(domain string, level Level, msg string)
+
+// LevelLogger is logger with leveled methods and key-value pairs, e.g. *slog.Logger
+type LevelLogger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// StructuredLogger adapts leveled structured logger, e.g. *slog.Logger, to LogFunc.
+//
+// Log domain is passed with key "domain". Error and critical messages are logged as errors,
+// message and info as info.
+func StructuredLogger(logger LevelLogger) LogFunc {
+	return func(domain string, level Level, msg string) {
+		switch level {
+		case LevelError, LevelCritical:
+			logger.Error(msg, "domain", domain)
+		case LevelWarning:
+			logger.Warn(msg, "domain", domain)
+		case LevelDebug:
+			logger.Debug(msg, "domain", domain)
+		default:
+			logger.Info(msg, "domain", domain)
+		}
+	}
+}
+
+var logHandler = &logState{
+	collectors: make(map[*warningsCollector]struct{}),
+}
+
+type logState struct {
+	fn         LogFunc
+	collectors map[*warningsCollector]struct{}
+	mu         sync.RWMutex
+}
+
+// SetLogger routes GLib and libvips log messages to fn instead of stderr.
+//
+// Pass nil to restore default GLib handler. fn can be called from libvips threads.
+// Handler is installed by Initialize(), messages before it are written to stderr.
+func SetLogger(fn LogFunc) {
+	logHandler.mu.Lock()
+	logHandler.fn = fn
+	logHandler.mu.Unlock()
+}
+
+func logSetHandler() {
+	C.imgvips_log_set_handler()
+}
+
+func (l *logState) log(domain string, level Level, msg string) {
+	l.mu.RLock()
+	if level <= LevelWarning {
+		for c := range l.collectors {
+			c.add(domain, msg)
+		}
+	}
+	// Handler is called without lock, so it can call SetLogger or log again
+	fn := l.fn
+	l.mu.RUnlock()
+
+	if fn != nil {
+		fn(domain, level, msg)
+
+		return
+	}
+
+	cDomain := C.CString(domain)
+	defer C.free(unsafe.Pointer(cDomain))
+	cMsg := C.CString(msg)
+	defer C.free(unsafe.Pointer(cMsg))
+
+	C.imgvips_log_default(cDomain, C.int(level), cMsg)
+}
+
+func (l *logState) collect() *warningsCollector {
+	c := &warningsCollector{}
+
+	l.mu.Lock()
+	l.collectors[c] = struct{}{}
+	l.mu.Unlock()
+
+	return c
+}
+
+func (l *logState) stop(c *warningsCollector) {
+	l.mu.Lock()
+	delete(l.collectors, c)
+	l.mu.Unlock()
+}
+
+// warningsCollector keeps first warning logged while operation executes.
+// Log messages are global, so warnings of concurrent operations are collected too.
+type warningsCollector struct {
+	err error
+	mu  sync.Mutex
+}
+
+func (c *warningsCollector) add(domain, msg string) {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = fmt.Errorf("%w: %s: %s", ErrWarning, domain, msg)
+	}
+	c.mu.Unlock()
+}
+
+func (c *warningsCollector) error() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
@@ -0,0 +1,102 @@
+package imgvips_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+type testLogger struct {
+	messages []string
+	mu       sync.Mutex
+}
+
+func (l *testLogger) add(level, msg string) {
+	l.mu.Lock()
+	l.messages = append(l.messages, level+": "+msg)
+	l.mu.Unlock()
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.add("debug", msg) }
+func (l *testLogger) Info(msg string, args ...interface{})  { l.add("info", msg) }
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.add("warn", msg) }
+func (l *testLogger) Error(msg string, args ...interface{}) { l.add("error", msg) }
+
+// resizeWithWarning sets optional argument with wrong type, so GLib logs warning
+func resizeWithWarning(t *testing.T, warningsAsErrors bool) error {
+	in, op := generateImage(t)
+	defer op.Free()
+
+	resizeOp, err := imgvips.NewOperation("resize")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer resizeOp.Free()
+
+	resizeOp.SetWarningsAsErrors(warningsAsErrors)
+	resizeOp.AddInput("in", in)
+	resizeOp.AddInput("scale", imgvips.GDouble(0.5))
+	resizeOp.AddInput("vscale", imgvips.GString("foo"))
+	resizeOp.AddOutput("out", imgvips.GNullVipsImage())
+
+	return resizeOp.Exec()
+}
+
+func TestSetLogger(t *testing.T) {
+	initVips(t)
+
+	logger := &testLogger{}
+	imgvips.SetLogger(imgvips.StructuredLogger(logger))
+	defer imgvips.SetLogger(nil)
+
+	if err := resizeWithWarning(t, false); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	if len(logger.messages) == 0 {
+		t.Fatal("Expected logged warning")
+	}
+}
+
+func TestSetLogger_ResetInHandler(t *testing.T) {
+	initVips(t)
+	defer imgvips.SetLogger(nil)
+
+	var calls int
+	imgvips.SetLogger(func(domain string, level imgvips.Level, msg string) {
+		calls++
+		imgvips.SetLogger(nil)
+	})
+
+	if err := resizeWithWarning(t, false); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected %d handler calls, got %d", 1, calls)
+	}
+}
+
+func TestOperation_SetWarningsAsErrors(t *testing.T) {
+	initVips(t)
+
+	imgvips.SetLogger(func(domain string, level imgvips.Level, msg string) {})
+	defer imgvips.SetLogger(nil)
+
+	if err := resizeWithWarning(t, true); !errors.Is(err, imgvips.ErrWarning) {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrWarning, err)
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	if imgvips.LevelWarning.String() != "warning" {
+		t.Errorf("Expected %s, got %s", "warning", imgvips.LevelWarning.String())
+	}
+	if imgvips.Level(0).String() != "level(0)" {
+		t.Errorf("Expected %s, got %s", "level(0)", imgvips.Level(0).String())
+	}
+}
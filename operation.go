@@ -4,12 +4,22 @@ package imgvips
 #cgo pkg-config: vips
 #include "stdlib.h"
 #include "vips/vips.h"
+
+static const char *imgvips_operation_nickname(VipsOperation *op) {
+	return VIPS_OBJECT_GET_CLASS(op)->nickname;
+}
+
+static gboolean imgvips_operation_is_load(VipsOperation *op) {
+	return g_type_is_a(G_OBJECT_TYPE(op), vips_foreign_load_get_type());
+}
 */
 import "C"
 
 import (
 	"errors"
 	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -21,14 +31,33 @@ var (
 // NewOperation initialize new *C.VipsOperation.
 //
 // If libvips don't known operation with provided name, function return error.
+// If operation is loader, forbidden by SetAllowedLoaders() or SetBlockedLoaders(), function return ErrLoaderNotAllowed.
+// After Shutdown(), function return ErrNotInitialized.
 func NewOperation(name string) (*Operation, error) {
+	// Hold state lock while operation is created, so Shutdown can't stop libvips meanwhile
+	vipsState.mu.RLock()
+	defer vipsState.mu.RUnlock()
+
+	if vipsState.shutdown {
+		return nil, ErrNotInitialized
+	}
+
 	op := C.vips_operation_new(cStringsCache.get(name))
 	if op == nil {
 		return nil, vipsError()
 	}
 
+	if C.imgvips_operation_is_load(op) != 0 && !loadersPolicy.isAllowed(operationNickname(op)) {
+		C.g_object_unref(C.gpointer(op))
+
+		return nil, ErrLoaderNotAllowed
+	}
+
+	atomic.AddInt64(&liveOperations, 1)
+
 	return &Operation{
 		operation: op,
+		limits:    defaultLimits.get(),
 	}, nil
 }
 
@@ -40,7 +69,33 @@ type Operation struct {
 
 	inputs  []*Argument
 	outputs []*Argument
-	mu      sync.Mutex
+	limits  Limits
+	// warningsAsErrors escalates warnings logged while executing to Exec() error
+	warningsAsErrors bool
+	mu               sync.Mutex
+}
+
+// SetWarningsAsErrors turns on/off escalation of GLib and libvips warnings to errors.
+//
+// If on, Exec() returns error wrapping ErrWarning, when warning was logged while operation executes,
+// e.g. "VipsJpeg: premature end of JPEG file" or failed property type conversion.
+// Log messages are global, so warnings of concurrently executed operations can be caught too.
+func (op *Operation) SetWarningsAsErrors(on bool) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	op.warningsAsErrors = on
+}
+
+// SetLimits set limits checked by Exec().
+//
+// VipsBlob inputs are checked before execution, output images headers are checked right after operation build,
+// before any pixels are computed. If limits exceeded, Exec() returns error wrapping ErrLimitExceeded.
+func (op *Operation) SetLimits(limits Limits) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	op.limits = limits
 }
 
 // AddInput adds argument for set to operation.
@@ -68,10 +123,35 @@ func (op *Operation) AddOutput(name string, value Value) {
 //
 // After execute all input arguments will be freed, all output arguments will be updated.
 // If operation return error, input arguments will be freed, all output arguments will not be updated and not be freed.
+// Operation limits are checked as described in SetLimits().
+// If tracer is set with SetTracer(), it is called before and after execution.
 func (op *Operation) Exec() error {
 	op.mu.Lock()
 	defer op.mu.Unlock()
 
+	tracer := getTracer()
+	if tracer == nil || op.operation == nil {
+		return op.exec()
+	}
+
+	name := operationNickname(op.operation)
+	args := make([]string, 0, len(op.inputs))
+	for _, arg := range op.inputs {
+		args = append(args, C.GoString(arg.name()))
+	}
+
+	tracer.OnStart(name, args)
+	start := time.Now()
+	memBefore := int64(C.vips_tracked_get_mem())
+
+	err := op.exec()
+
+	tracer.OnFinish(name, time.Since(start), int64(C.vips_tracked_get_mem())-memBefore, err)
+
+	return err
+}
+
+func (op *Operation) exec() error {
 	defer func(args []*Argument) {
 		for _, arg := range args {
 			arg.Free()
@@ -82,17 +162,43 @@ func (op *Operation) Exec() error {
 		return ErrOperationAlreadyFreed
 	}
 
+	if err := op.checkInputs(); err != nil {
+		return err
+	}
+
+	var warnings *warningsCollector
+	if op.warningsAsErrors {
+		warnings = logHandler.collect()
+		defer logHandler.stop(warnings)
+	}
+
 	for _, arg := range op.inputs {
 		C.g_object_set_property((*C.GObject)(unsafe.Pointer(op.operation)), arg.name(), (*C.GValue)(arg.value().Ptr()))
 	}
 
 	cOp := C.vips_cache_operation_build(op.operation)
 	if cOp == nil {
-		return vipsError()
+		err := vipsError()
+		if inErr := inputsError(op.inputs); inErr != nil {
+			return inErr
+		}
+
+		return err
 	}
 	C.g_object_unref(C.gpointer(op.operation))
 	op.operation = cOp
 
+	memHighwater.sample()
+
+	if err := op.checkOutputs(); err != nil {
+		return err
+	}
+	if warnings != nil {
+		if err := warnings.error(); err != nil {
+			return err
+		}
+	}
+
 	for _, arg := range op.outputs {
 		C.g_object_get_property((*C.GObject)(unsafe.Pointer(op.operation)), arg.name(), (*C.GValue)(arg.value().Ptr()))
 	}
@@ -115,8 +221,43 @@ func (op *Operation) Free() {
 
 	C.g_object_unref(C.gpointer(op.operation))
 	VipsErrorFree()
+	atomic.AddInt64(&liveOperations, -1)
 
 	op.operation = nil
 	op.inputs = nil
 	op.outputs = nil
 }
+
+// inputsError return first error produced by input values during operation build, e.g. io.Writer or io.Reader error
+func inputsError(args []*Argument) error {
+	for _, arg := range args {
+		val, ok := arg.value().(*GValue)
+		if !ok {
+			continue
+		}
+		if err := val.targetError(); err != nil {
+			return err
+		}
+		if err := val.sourceError(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nickname return operation nickname, e.g. webpload_buffer
+func (op *Operation) nickname() string {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	if op.operation == nil {
+		return ""
+	}
+
+	return operationNickname(op.operation)
+}
+
+func operationNickname(op *C.VipsOperation) string {
+	return C.GoString(C.imgvips_operation_nickname(op))
+}
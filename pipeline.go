@@ -0,0 +1,234 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoImageArgument returns when pipeline step operation has no required image input or output
+	ErrNoImageArgument = errors.New("operation has no image argument")
+	// ErrNoSource returns when pipeline executed without Load()
+	ErrNoSource = errors.New("pipeline has no source")
+)
+
+// StepError returns when pipeline step fails.
+// Step 0 is load, steps added by Then() are numbered from 1, save is the last step.
+type StepError struct {
+	Step      int
+	Operation string
+	Err       error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %d (%s): %v", e.Step, e.Operation, e.Err)
+}
+
+// Unwrap return underlying error
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+type pipelineStep struct {
+	name string
+	args Args
+}
+
+// Pipeline chains operations, passing main output image of each step to main input image of next step,
+// e.g. out of load to in of resize.
+//
+// Intermediate images are freed as soon as next step is built, libvips keeps references needed for computation.
+// Pipeline is not safe for concurrent use.
+type Pipeline struct {
+	source interface{}
+	steps  []pipelineStep
+}
+
+// NewPipeline create empty pipeline
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// Load set pipeline source.
+//
+// Source can be []byte (loaded with LoadBuffer), string (file name, loaded with LoadFile) or *GValue with image,
+// which is referenced, so it stays owned by caller.
+// Bytes array must be protected from GC and modification while pipeline result is used.
+func (p *Pipeline) Load(source interface{}) *Pipeline {
+	p.source = source
+
+	return p
+}
+
+// Then adds operation step. Arguments are converted as described in Args.
+func (p *Pipeline) Then(name string, args Args) *Pipeline {
+	p.steps = append(p.steps, pipelineStep{name: name, args: args})
+
+	return p
+}
+
+// Image executes pipeline and return its result image.
+// Returned value is owned by caller, call *GValue.Free() after image is no longer needed.
+func (p *Pipeline) Image() (*GValue, error) {
+	out, op, err := p.run()
+	if err != nil {
+		return nil, err
+	}
+	defer freeOperation(op)
+	defer out.Free()
+
+	return out.ref(), nil
+}
+
+// Save executes pipeline and saves result with saver detected by suffix, e.g. ".jpg" or ".webp".
+// Saver arguments are converted as described in Args.
+func (p *Pipeline) Save(suffix string, args Args) ([]byte, error) {
+	saveStep := len(p.steps) + 1
+
+	cOpName := C.vips_foreign_find_save_buffer(cStringsCache.get(suffix))
+	if cOpName == nil {
+		VipsErrorFree()
+
+		return nil, &StepError{Step: saveStep, Operation: suffix, Err: ErrUnknownFormat}
+	}
+	opName := C.GoString(cOpName)
+
+	in, last, err := p.run()
+	if err != nil {
+		return nil, err
+	}
+	defer freeOperation(last)
+	defer in.Free()
+
+	op, err := newStep(opName, args, in)
+	if err != nil {
+		return nil, &StepError{Step: saveStep, Operation: opName, Err: err}
+	}
+	defer op.Free()
+
+	buffer := GNullVipsBlob()
+	op.AddOutput("buffer", buffer)
+
+	if err := op.Exec(); err != nil {
+		return nil, &StepError{Step: saveStep, Operation: opName, Err: err}
+	}
+
+	data, _ := buffer.Bytes()
+
+	return data, nil
+}
+
+// run executes load and all steps, return last output and operation, which owns it
+func (p *Pipeline) run() (*GValue, *Operation, error) {
+	out, last, err := loadSource(p.source)
+	if err != nil {
+		return nil, nil, &StepError{Step: 0, Operation: "load", Err: err}
+	}
+
+	for i, step := range p.steps {
+		next, op, err := runStep(step.name, step.args, out)
+		// Step operation holds own reference to input image, or failed
+		freeOperation(last)
+		out.Free()
+		if err != nil {
+			return nil, nil, &StepError{Step: i + 1, Operation: step.name, Err: err}
+		}
+
+		out, last = next, op
+	}
+
+	return out, last, nil
+}
+
+// loadSource loads image from source as described in *Pipeline.Load().
+// Returned operation owns image and is nil, if source is *GValue.
+func loadSource(source interface{}) (*GValue, *Operation, error) {
+	switch src := source.(type) {
+	case []byte:
+		return LoadBuffer(src)
+	case string:
+		return LoadFile(src)
+	case *GValue:
+		if !src.isImage() {
+			return nil, nil, fmt.Errorf("%w: %T", ErrUnsupportedArgument, src)
+		}
+		if src.wasFreed() {
+			return nil, nil, ErrImageAlreadyFreed
+		}
+
+		return src.ref(), nil, nil
+	case nil:
+		return nil, nil, ErrNoSource
+	}
+
+	return nil, nil, fmt.Errorf("%w: %T", ErrUnsupportedArgument, source)
+}
+
+// runStep executes operation with in as main input image and return its main output image.
+// In is freed after execution.
+func runStep(name string, args Args, in *GValue) (*GValue, *Operation, error) {
+	op, err := newStep(name, args, in)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, output := op.mainImageArguments()
+	if output == "" {
+		op.Free()
+
+		return nil, nil, ErrNoImageArgument
+	}
+
+	out := GNullVipsImage()
+	op.AddOutput(output, out)
+
+	if err := op.Exec(); err != nil {
+		op.Free()
+
+		return nil, nil, err
+	}
+
+	return out, op, nil
+}
+
+// newStep create operation with in as main input image and args as other inputs
+func newStep(name string, args Args, in *GValue) (*Operation, error) {
+	op, err := NewOperation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	input, _ := op.mainImageArguments()
+	if input == "" {
+		op.Free()
+
+		return nil, ErrNoImageArgument
+	}
+
+	names, values, err := args.values()
+	if err != nil {
+		op.Free()
+
+		return nil, err
+	}
+
+	op.AddInput(input, in)
+	for i, name := range names {
+		op.AddInput(name, values[i])
+	}
+
+	return op, nil
+}
+
+func freeOperation(op *Operation) {
+	if op != nil {
+		op.Free()
+	}
+}
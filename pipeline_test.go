@@ -0,0 +1,131 @@
+package imgvips_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestPipeline_Save(t *testing.T) {
+	initVips(t)
+
+	data, err := ioutil.ReadFile("./tests/fixtures/small.webp")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	result, err := imgvips.NewPipeline().
+		Load(data).
+		Then("resize", imgvips.Args{"scale": 0.5}).
+		Then("sharpen", nil).
+		Save(".jpg", imgvips.Args{"Q": 85})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	info, err := imgvips.Probe(result)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if info.Format != "jpeg" {
+		t.Errorf("Expected format %s, got %s", "jpeg", info.Format)
+	}
+}
+
+func TestPipeline_Image(t *testing.T) {
+	initVips(t)
+
+	in, op := generateImage(t)
+	defer op.Free()
+
+	out, err := imgvips.NewPipeline().
+		Load(in).
+		Then("resize", imgvips.Args{"scale": 0.5}).
+		Then("flip", imgvips.Args{"direction": 0}).
+		Image()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer out.Free()
+
+	img, ok := out.Image()
+	if !ok || img == nil {
+		t.Fatal("Expected *C.VipsImage in out")
+	}
+	if img.Width() != 50 || img.Height() != 50 {
+		t.Errorf("Expected size %dx%d, got %dx%d", 50, 50, img.Width(), img.Height())
+	}
+
+	if src, ok := in.Image(); !ok || src == nil || src.Width() != 100 {
+		t.Error("Expected source image stays owned by caller")
+	}
+}
+
+func TestPipeline_ImageSource(t *testing.T) {
+	initVips(t)
+
+	in, op := generateImage(t)
+	defer op.Free()
+
+	out, err := imgvips.NewPipeline().Load(in).Image()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	out.Free()
+
+	if src, ok := in.Image(); !ok || src == nil || src.Width() != 100 {
+		t.Error("Expected source image stays owned by caller")
+	}
+
+	freed := imgvips.GNullVipsImage()
+	freed.Free()
+	if _, err := imgvips.NewPipeline().Load(freed).Image(); !errors.Is(err, imgvips.ErrImageAlreadyFreed) {
+		t.Errorf("Expected ErrImageAlreadyFreed, got %v", err)
+	}
+}
+
+func TestPipeline_StepError(t *testing.T) {
+	initVips(t)
+
+	in, op := generateImage(t)
+	defer op.Free()
+
+	cases := []struct {
+		name      string
+		pipeline  *imgvips.Pipeline
+		step      int
+		operation string
+		err       error
+	}{
+		{"no source", imgvips.NewPipeline().Then("sharpen", nil), 0, "load", imgvips.ErrNoSource},
+		{"unknown operation", imgvips.NewPipeline().Load(in).Then("sharpen", nil).Then("foobar", nil), 2, "foobar", nil},
+		{"no image", imgvips.NewPipeline().Load(in).Then("black", nil), 1, "black", imgvips.ErrNoImageArgument},
+		{"argument", imgvips.NewPipeline().Load(in).Then("resize", imgvips.Args{"scale": struct{}{}}), 1, "resize", imgvips.ErrUnsupportedArgument},
+		{"int64 argument", imgvips.NewPipeline().Load(in).Then("resize", imgvips.Args{"scale": 0.5, "kernel": int64(math.MaxInt32) + 1}), 1, "resize", imgvips.ErrUnsupportedArgument},
+		{"exec", imgvips.NewPipeline().Load(in).Then("resize", nil), 1, "resize", nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.pipeline.Save(".png", nil)
+
+			var stepErr *imgvips.StepError
+			if !errors.As(err, &stepErr) {
+				t.Fatalf("Expected *imgvips.StepError, got %v", err)
+			}
+			if stepErr.Step != c.step || stepErr.Operation != c.operation {
+				t.Errorf("Expected step %d (%s), got %d (%s)", c.step, c.operation, stepErr.Step, stepErr.Operation)
+			}
+			if c.err != nil && !errors.Is(err, c.err) {
+				t.Errorf("Expected error %v, got %v", c.err, err)
+			}
+		})
+	}
+
+	if _, err := imgvips.NewPipeline().Load(in).Save(".foobar", nil); !errors.Is(err, imgvips.ErrUnknownFormat) {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrUnknownFormat, err)
+	}
+}
@@ -0,0 +1,87 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrUnknownFormat returns when libvips don't known how to load data
+	ErrUnknownFormat = errors.New("libvips don't known how to load data")
+)
+
+// loaderSuffixes are suffixes of loader nicknames, longest first
+var loaderSuffixes = []string{"load_buffer", "load_source", "load"}
+
+// ImageInfo contains image header information
+type ImageInfo struct {
+	// Format is name of libvips loader without load suffix, e.g. jpeg, png, webp
+	Format        string
+	Width         int
+	Height        int
+	Bands         int
+	Pages         int
+	Orientation   int
+	HasICCProfile bool
+	HasAlpha      bool
+}
+
+// Probe reads image header from bytes array.
+//
+// Only header is read, pixels are not decoded. Limits set by SetLimits() are checked.
+func Probe(data []byte) (ImageInfo, error) {
+	out, op, err := LoadBuffer(data)
+	if err != nil {
+		return ImageInfo{}, err
+	}
+	defer op.Free()
+
+	return probe(out, op)
+}
+
+// ProbeFile reads image header from file.
+//
+// If file can't be opened, *os.PathError is returned, e.g. with os.ErrNotExist.
+// Only header is read, pixels are not decoded. Limits set by SetLimits() are checked.
+func ProbeFile(filename string) (ImageInfo, error) {
+	out, op, err := LoadFile(filename)
+	if err != nil {
+		return ImageInfo{}, err
+	}
+	defer op.Free()
+
+	return probe(out, op)
+}
+
+func probe(out *GValue, op *Operation) (ImageInfo, error) {
+	img, ok := out.Image()
+	if !ok || img == nil {
+		return ImageInfo{}, ErrUnknownFormat
+	}
+
+	nickname := op.nickname()
+	for _, suffix := range loaderSuffixes {
+		if strings.HasSuffix(nickname, suffix) {
+			nickname = strings.TrimSuffix(nickname, suffix)
+			break
+		}
+	}
+
+	return ImageInfo{
+		Format:        nickname,
+		Width:         img.Width(),
+		Height:        img.Height(),
+		Bands:         img.Bands(),
+		Pages:         img.Pages(),
+		Orientation:   img.Orientation(),
+		HasICCProfile: img.HasICCProfile(),
+		HasAlpha:      img.HasAlpha(),
+	}, nil
+}
@@ -0,0 +1,64 @@
+package imgvips_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestProbe(t *testing.T) {
+	initVips(t)
+
+	data, err := ioutil.ReadFile("./tests/fixtures/small.webp")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	info, err := imgvips.Probe(data)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	fileInfo, err := imgvips.ProbeFile("./tests/fixtures/small.webp")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if info != fileInfo {
+		t.Errorf("Expected same info %+v, got %+v", info, fileInfo)
+	}
+	if info.Format != "webp" {
+		t.Errorf("Expected format %s, got %s", "webp", info.Format)
+	}
+	if info.Width <= 0 || info.Height <= 0 {
+		t.Errorf("Expected positive size, got %dx%d", info.Width, info.Height)
+	}
+	if info.Bands < 3 {
+		t.Errorf("Expected at least %d bands, got %d", 3, info.Bands)
+	}
+	if info.Pages != 1 {
+		t.Errorf("Expected %d pages, got %d", 1, info.Pages)
+	}
+	if info.Orientation != 1 {
+		t.Errorf("Expected orientation %d, got %d", 1, info.Orientation)
+	}
+	if info.HasAlpha != (info.Bands == 4) {
+		t.Errorf("Expected alpha %v for %d bands", info.Bands == 4, info.Bands)
+	}
+}
+
+func TestProbe_Unknown(t *testing.T) {
+	initVips(t)
+
+	if _, err := imgvips.Probe([]byte("foobar")); err != imgvips.ErrUnknownFormat {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrUnknownFormat, err)
+	}
+	if _, err := imgvips.Probe(nil); err != imgvips.ErrUnknownFormat {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrUnknownFormat, err)
+	}
+	if _, err := imgvips.ProbeFile("./tests/fixtures/not_exists.webp"); !os.IsNotExist(err) {
+		t.Errorf("Expected not exist error, got %v", err)
+	}
+}
@@ -0,0 +1,326 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrInvalidRecipe returns when recipe can't be decoded
+	ErrInvalidRecipe = errors.New("invalid recipe")
+	// ErrUnknownOperation returns when libvips don't known operation used in recipe
+	ErrUnknownOperation = errors.New("unknown operation")
+	// ErrUnknownArgument returns when operation has no input argument used in recipe
+	ErrUnknownArgument = errors.New("unknown argument")
+	// ErrMissingArgument returns when required operation argument is not set in recipe
+	ErrMissingArgument = errors.New("missing required argument")
+)
+
+// RecipeError returns when recipe step is invalid.
+// Path points to invalid value, e.g. [1].args.crop for args of second step.
+type RecipeError struct {
+	Path string
+	Err  error
+}
+
+func (e *RecipeError) Error() string {
+	return fmt.Sprintf("recipe %s: %v", e.Path, e.Err)
+}
+
+// Unwrap return underlying error
+func (e *RecipeError) Unwrap() error {
+	return e.Err
+}
+
+// RecipeStep is operation with arguments, e.g. {"op": "thumbnail_image", "args": {"width": 300, "crop": "attention"}}.
+// Main input image of operation is set to output image of previous step.
+type RecipeStep struct {
+	Op   string                 `json:"op" yaml:"op"`
+	Args map[string]interface{} `json:"args,omitempty" yaml:"args,omitempty"`
+}
+
+// Recipe is validated list of operations, which can be executed against many images
+type Recipe struct {
+	steps []pipelineStep
+}
+
+// LoadRecipe reads JSON list of steps and validates them with NewRecipe().
+//
+// To load recipe in other format, e.g. YAML, decode it to []RecipeStep and call NewRecipe().
+func LoadRecipe(r io.Reader) (*Recipe, error) {
+	var steps []RecipeStep
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&steps); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecipe, err)
+	}
+
+	return NewRecipe(steps)
+}
+
+// NewRecipe validates steps and converts arguments using operations introspection.
+//
+// Arguments are converted by type of operation argument:
+//   - bool: boolean
+//   - int, double: number in argument range
+//   - int64, uint64: number in argument range, limited to int32
+//   - string: string
+//   - enum: nick (e.g. attention) or number
+//   - flags: nick, list of nicks or number
+//   - VipsArrayDouble, VipsArrayInt: list of numbers or single number
+//
+// Integer, enum and flags values are set as GInt, GLib transforms them to argument type on set.
+//
+// If step is invalid, *RecipeError returned.
+func NewRecipe(steps []RecipeStep) (*Recipe, error) {
+	recipe := &Recipe{steps: make([]pipelineStep, 0, len(steps))}
+
+	for i, step := range steps {
+		args, err := recipeStepArgs(step, fmt.Sprintf("[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+
+		recipe.steps = append(recipe.steps, pipelineStep{name: step.Op, args: args})
+	}
+
+	return recipe, nil
+}
+
+// Pipeline create pipeline from source with recipe steps
+func (r *Recipe) Pipeline(source interface{}) *Pipeline {
+	p := NewPipeline().Load(source)
+	for _, step := range r.steps {
+		p.Then(step.name, step.args)
+	}
+
+	return p
+}
+
+// Exec executes recipe against image.
+// Returned value is owned by caller, call *GValue.Free() after image is no longer needed.
+func (r *Recipe) Exec(in *GValue) (*GValue, error) {
+	return r.Pipeline(in).Image()
+}
+
+func recipeStepArgs(step RecipeStep, path string) (Args, error) {
+	if step.Op == "" || !HasOperation(step.Op) {
+		return nil, &RecipeError{Path: path + ".op", Err: fmt.Errorf("%w: %q", ErrUnknownOperation, step.Op)}
+	}
+
+	op, err := NewOperation(step.Op)
+	if err != nil {
+		return nil, &RecipeError{Path: path + ".op", Err: err}
+	}
+	defer op.Free()
+
+	input, output := op.mainImageArguments()
+	if input == "" || output == "" {
+		return nil, &RecipeError{Path: path + ".op", Err: ErrNoImageArgument}
+	}
+
+	arguments := make(map[string]argumentInfo)
+	for _, arg := range operationArguments(op.operation) {
+		if arg.is(C.VIPS_ARGUMENT_INPUT) && arg.name != input {
+			arguments[arg.name] = arg
+		}
+	}
+
+	names := make([]string, 0, len(step.Args))
+	for name := range step.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make(Args, len(step.Args))
+	for _, name := range names {
+		argPath := path + ".args." + name
+
+		arg, ok := arguments[name]
+		if !ok {
+			return nil, &RecipeError{Path: argPath, Err: fmt.Errorf("%w: %s has no input %s", ErrUnknownArgument, step.Op, name)}
+		}
+
+		value, err := recipeValue(arg, step.Args[name])
+		if err != nil {
+			return nil, &RecipeError{Path: argPath, Err: err}
+		}
+		args[name] = value
+	}
+
+	required := make([]string, 0, len(arguments))
+	for name, arg := range arguments {
+		if _, ok := args[name]; !ok && arg.is(C.VIPS_ARGUMENT_REQUIRED) {
+			required = append(required, name)
+		}
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+
+		return nil, &RecipeError{Path: path + ".args." + required[0], Err: ErrMissingArgument}
+	}
+
+	return args, nil
+}
+
+// recipeValue converts decoded value to Go value accepted by Args with type of operation argument
+func recipeValue(arg argumentInfo, raw interface{}) (interface{}, error) {
+	switch {
+	case arg.gType == C.vips_array_double_get_type():
+		return recipeNumbers(raw, false)
+	case arg.gType == C.vips_array_int_get_type():
+		numbers, err := recipeNumbers(raw, true)
+		if err != nil {
+			return nil, err
+		}
+		ints := make([]int, len(numbers))
+		for i, n := range numbers {
+			ints[i] = int(n)
+		}
+
+		return ints, nil
+	}
+
+	switch arg.fundamental() {
+	case C.G_TYPE_BOOLEAN:
+		if v, ok := raw.(bool); ok {
+			return v, nil
+		}
+	case C.G_TYPE_STRING:
+		if v, ok := raw.(string); ok {
+			return v, nil
+		}
+	case C.G_TYPE_INT, C.G_TYPE_INT64, C.G_TYPE_UINT64:
+		// 64-bit arguments are set with GInt and transformed by GLib, so they are limited to int32
+		if n, ok := recipeNumber(raw); ok && n == math.Trunc(n) {
+			if min, max := arg.intRange(); n < float64(min) || n > float64(max) {
+				return nil, fmt.Errorf("%w: %v out of range [%d, %d]", ErrUnsupportedArgument, n, min, max)
+			}
+
+			return int(n), nil
+		}
+	case C.G_TYPE_DOUBLE:
+		if n, ok := recipeNumber(raw); ok {
+			if min, max := arg.doubleRange(); n < min || n > max {
+				return nil, fmt.Errorf("%w: %v out of range [%g, %g]", ErrUnsupportedArgument, n, min, max)
+			}
+
+			return n, nil
+		}
+	case C.G_TYPE_ENUM:
+		return recipeEnum(arg, raw)
+	case C.G_TYPE_FLAGS:
+		return recipeFlags(arg, raw)
+	default:
+		return nil, fmt.Errorf("%w: argument type %s", ErrUnsupportedArgument, C.GoString(C.g_type_name(arg.gType)))
+	}
+
+	return nil, fmt.Errorf("%w: %T for %s argument", ErrUnsupportedArgument, raw, C.GoString(C.g_type_name(arg.gType)))
+}
+
+func recipeEnum(arg argumentInfo, raw interface{}) (interface{}, error) {
+	if nick, ok := raw.(string); ok {
+		if value, ok := arg.enumValue(nick); ok {
+			return value, nil
+		}
+
+		return nil, fmt.Errorf("%w: %q, expected one of %s", ErrUnsupportedArgument, nick, strings.Join(arg.enumNicks(), ", "))
+	}
+
+	if n, ok := recipeNumber(raw); ok && n == math.Trunc(n) && arg.enumHas(int(n)) {
+		return int(n), nil
+	}
+
+	return nil, fmt.Errorf("%w: %v, expected one of %s", ErrUnsupportedArgument, raw, strings.Join(arg.enumNicks(), ", "))
+}
+
+func recipeFlags(arg argumentInfo, raw interface{}) (interface{}, error) {
+	if n, ok := recipeNumber(raw); ok && n == math.Trunc(n) && n >= 0 {
+		return int(n), nil
+	}
+
+	var nicks []interface{}
+	switch v := raw.(type) {
+	case string:
+		nicks = []interface{}{v}
+	case []interface{}:
+		nicks = v
+	default:
+		return nil, fmt.Errorf("%w: %T for flags argument", ErrUnsupportedArgument, raw)
+	}
+
+	flags := 0
+	for _, nick := range nicks {
+		s, ok := nick.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T for flags argument", ErrUnsupportedArgument, nick)
+		}
+		value, ok := arg.flagsValue(s)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown flag %q", ErrUnsupportedArgument, s)
+		}
+		flags |= value
+	}
+
+	return flags, nil
+}
+
+// recipeNumbers converts list of numbers or single number
+func recipeNumbers(raw interface{}, integer bool) ([]float64, error) {
+	items, ok := raw.([]interface{})
+	if !ok {
+		items = []interface{}{raw}
+	}
+
+	numbers := make([]float64, 0, len(items))
+	for i, item := range items {
+		n, ok := recipeNumber(item)
+		if !ok || (integer && n != math.Trunc(n)) {
+			return nil, fmt.Errorf("%w: %v at index %d", ErrUnsupportedArgument, item, i)
+		}
+		numbers = append(numbers, n)
+	}
+
+	return numbers, nil
+}
+
+// recipeNumber converts number decoded by encoding/json or other decoders, e.g. YAML
+func recipeNumber(raw interface{}) (float64, bool) {
+	switch v := raw.(type) {
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,112 @@
+package imgvips_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestLoadRecipe(t *testing.T) {
+	initVips(t)
+
+	recipe, err := imgvips.LoadRecipe(strings.NewReader(`[
+		{"op": "thumbnail_image", "args": {"width": 50, "crop": "attention", "linear": false}},
+		{"op": "embed", "args": {"x": 5, "y": 5, "width": 60, "height": 60, "extend": "background", "background": [255]}},
+		{"op": "sharpen"}
+	]`))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	in, op := generateImage(t)
+	defer op.Free()
+
+	out, err := recipe.Exec(in)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer out.Free()
+
+	img, ok := out.Image()
+	if !ok || img == nil {
+		t.Fatal("Expected *C.VipsImage in out")
+	}
+	if img.Width() != 60 || img.Height() != 60 {
+		t.Errorf("Expected size %dx%d, got %dx%d", 60, 60, img.Width(), img.Height())
+	}
+}
+
+func TestLoadRecipe_Invalid(t *testing.T) {
+	initVips(t)
+
+	cases := []struct {
+		name   string
+		recipe string
+		path   string
+		err    error
+	}{
+		{"syntax", `[{"op": }]`, "", imgvips.ErrInvalidRecipe},
+		{"unknown field", `[{"op": "sharpen", "foo": 1}]`, "", imgvips.ErrInvalidRecipe},
+		{"unknown operation", `[{"op": "sharpen"}, {"op": "foobar"}]`, "[1].op", imgvips.ErrUnknownOperation},
+		{"no image", `[{"op": "black", "args": {"width": 1, "height": 1}}]`, "[0].op", imgvips.ErrNoImageArgument},
+		{"unknown argument", `[{"op": "sharpen", "args": {"foo": 1}}]`, "[0].args.foo", imgvips.ErrUnknownArgument},
+		{"missing argument", `[{"op": "thumbnail_image"}]`, "[0].args.width", imgvips.ErrMissingArgument},
+		{"enum", `[{"op": "thumbnail_image", "args": {"width": 1, "crop": "foo"}}]`, "[0].args.crop", imgvips.ErrUnsupportedArgument},
+		{"int", `[{"op": "thumbnail_image", "args": {"width": 1.5}}]`, "[0].args.width", imgvips.ErrUnsupportedArgument},
+		{"range", `[{"op": "thumbnail_image", "args": {"width": -1}}]`, "[0].args.width", imgvips.ErrUnsupportedArgument},
+		{"bool", `[{"op": "thumbnail_image", "args": {"width": 1, "linear": "yes"}}]`, "[0].args.linear", imgvips.ErrUnsupportedArgument},
+		{"array", `[{"op": "embed", "args": {"x": 0, "y": 0, "width": 1, "height": 1, "background": ["white"]}}]`, "[0].args.background", imgvips.ErrUnsupportedArgument},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := imgvips.LoadRecipe(strings.NewReader(c.recipe))
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if c.path == "" {
+				return
+			}
+
+			var recipeErr *imgvips.RecipeError
+			if !errors.As(err, &recipeErr) {
+				t.Fatalf("Expected *imgvips.RecipeError, got %v", err)
+			}
+			if recipeErr.Path != c.path {
+				t.Errorf("Expected path %s, got %s", c.path, recipeErr.Path)
+			}
+		})
+	}
+}
+
+func TestNewRecipe(t *testing.T) {
+	initVips(t)
+
+	// Values decoded by YAML decoders
+	recipe, err := imgvips.NewRecipe([]imgvips.RecipeStep{
+		{Op: "resize", Args: map[string]interface{}{"scale": 0.5, "kernel": "nearest"}},
+		{Op: "flip", Args: map[string]interface{}{"direction": 0}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	in, op := generateImage(t)
+	defer op.Free()
+
+	out, err := recipe.Exec(in)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer out.Free()
+
+	img, ok := out.Image()
+	if !ok || img == nil {
+		t.Fatal("Expected *C.VipsImage in out")
+	}
+	if img.Width() != 50 {
+		t.Errorf("Expected width %d, got %d", 50, img.Width())
+	}
+}
@@ -0,0 +1,188 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "vips/vips.h"
+*/
+import "C"
+
+import (
+	"math"
+)
+
+// smartCropSteps is number of candidate window positions on each axis, checked by fallback
+const smartCropSteps = 4
+
+// CropArea is area of source image, which was cropped
+type CropArea struct {
+	Left   int
+	Top    int
+	Width  int
+	Height int
+}
+
+// SmartCrop crops image to width*height, keeping most interesting area, and return chosen area.
+// Width and height larger than image are clipped to image size.
+//
+// If libvips has no smartcrop operation (added in 8.5), candidate windows are scored in Go:
+// InterestingEntropy chooses window with highest hist_entropy,
+// InterestingLow and InterestingHigh choose window at low or high coordinates,
+// other strategies choose centre.
+// InterestingAttention has no fallback, so it is scored as InterestingEntropy, and crop may differ from libvips 8.5+.
+//
+// Returned value is owned by caller, call *GValue.Free() after image is no longer needed.
+func SmartCrop(img *Image, width, height int, interesting Interesting) (*GValue, CropArea, error) {
+	if img.val.wasFreed() {
+		return nil, CropArea{}, ErrImageAlreadyFreed
+	}
+
+	area := CropArea{
+		Width:  minInt(width, img.Width()),
+		Height: minInt(height, img.Height()),
+	}
+	if area.Width <= 0 || area.Height <= 0 {
+		return nil, CropArea{}, ErrInvalidGeometry
+	}
+
+	if HasOperation("smartcrop") {
+		out, err := img.transform("smartcrop", Args{"width": area.Width, "height": area.Height, "interesting": int(interesting)})
+		if err != nil {
+			return nil, CropArea{}, err
+		}
+
+		// extract_area, used by smartcrop, keeps position of area in negative offsets
+		result, _ := out.Image()
+		area.Left, area.Top = result.offset()
+
+		return out, area, nil
+	}
+
+	area.Left, area.Top = (img.Width()-area.Width)/2, (img.Height()-area.Height)/2
+
+	switch interesting {
+	case InterestingLow:
+		area.Left, area.Top = 0, 0
+	case InterestingHigh:
+		area.Left, area.Top = img.Width()-area.Width, img.Height()-area.Height
+	case InterestingEntropy, InterestingAttention:
+		var err error
+		if area, err = smartCropScore(img, area); err != nil {
+			return nil, CropArea{}, err
+		}
+	}
+
+	out, err := img.ExtractArea(area.Left, area.Top, area.Width, area.Height)
+	if err != nil {
+		return nil, CropArea{}, err
+	}
+
+	return out, area, nil
+}
+
+// smartCropScore scores candidate windows of greyscale image by entropy and return the best one
+func smartCropScore(img *Image, area CropArea) (CropArea, error) {
+	grey, err := smartCropGrey(img)
+	if err != nil {
+		return area, err
+	}
+	defer grey.Free()
+
+	greyImg, _ := grey.Image()
+	best := area
+	bestScore := math.Inf(-1)
+
+	for y := 0; y <= smartCropSteps; y++ {
+		for x := 0; x <= smartCropSteps; x++ {
+			candidate := area
+			candidate.Left = (img.Width() - area.Width) * x / smartCropSteps
+			candidate.Top = (img.Height() - area.Height) * y / smartCropSteps
+
+			score, err := smartCropEntropy(greyImg, candidate)
+			if err != nil {
+				return area, err
+			}
+			if score > bestScore {
+				best, bestScore = candidate, score
+			}
+
+			if img.Width() == area.Width {
+				break
+			}
+		}
+
+		if img.Height() == area.Height {
+			break
+		}
+	}
+
+	return best, nil
+}
+
+// smartCropGrey converts image to uchar greyscale, as required by hist_find
+func smartCropGrey(img *Image) (*GValue, error) {
+	in := img.val.ref()
+	defer in.Free()
+
+	inImg, _ := in.Image()
+	if img.Bands() >= 3 {
+		grey, err := inImg.transform("colourspace", Args{"space": int(InterpretationBW)})
+		if err != nil {
+			return nil, err
+		}
+		defer grey.Free()
+
+		inImg, _ = grey.Image()
+	}
+
+	return inImg.transform("cast", Args{"format": int(BandFormatUchar)})
+}
+
+func smartCropEntropy(grey *Image, area CropArea) (float64, error) {
+	window, err := grey.ExtractArea(area.Left, area.Top, area.Width, area.Height)
+	if err != nil {
+		return 0, err
+	}
+	defer window.Free()
+
+	windowImg, _ := window.Image()
+
+	hist, err := windowImg.transform("hist_find", nil)
+	if err != nil {
+		return 0, err
+	}
+	defer hist.Free()
+
+	histImg, _ := hist.Image()
+
+	return histImg.double("hist_entropy")
+}
+
+// double executes operation with image as main input and return its double output, e.g. avg
+func (i *Image) double(name string) (float64, error) {
+	op, err := NewOperation(name)
+	if err != nil {
+		return 0, err
+	}
+	defer op.Free()
+
+	out := GDouble(0)
+	op.AddInput("in", i.val.ref())
+	op.AddOutput("out", out)
+
+	if err := op.Exec(); err != nil {
+		return 0, err
+	}
+
+	value, _ := out.Double()
+
+	return value, nil
+}
+
+// offset return position of image in its source, set by extract_area
+func (i *Image) offset() (left, top int) {
+	if i.val.wasFreed() {
+		return 0, 0
+	}
+
+	return -int(C.vips_image_get_xoffset(i.image)), -int(C.vips_image_get_yoffset(i.image))
+}
@@ -0,0 +1,39 @@
+package imgvips
+
+import (
+	"testing"
+)
+
+// Fallback is used only with libvips without smartcrop operation, so scoring is called directly
+func TestSmartCropScore(t *testing.T) {
+	initVipsInternal(t)
+
+	// Image is black except noisy band at x in [60, 80)
+	width, height := 100, 20
+	data := make([]byte, width*height)
+	for y := 0; y < height; y++ {
+		for x := 60; x < 80; x++ {
+			if (x+y)%2 == 0 {
+				data[y*width+x] = 255
+			}
+		}
+	}
+
+	val, err := NewImageFromMemory(data, width, height, 1, BandFormatUchar)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer val.Free()
+
+	img, _ := val.Image()
+
+	area, err := smartCropScore(img, CropArea{Left: 40, Width: 20, Height: 20})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	expected := CropArea{Left: 60, Top: 0, Width: 20, Height: 20}
+	if area != expected {
+		t.Errorf("Expected area %+v, got %+v", expected, area)
+	}
+}
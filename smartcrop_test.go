@@ -0,0 +1,84 @@
+package imgvips_test
+
+import (
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestSmartCrop(t *testing.T) {
+	initVips(t)
+
+	val := noisyBandImage(t)
+	defer val.Free()
+
+	img, ok := val.Image()
+	if !ok || img == nil {
+		t.Fatal("Expected *C.VipsImage in val")
+	}
+
+	// Entropy window position depends on libvips search steps, so left -1 means it is only checked to overlap noisy band
+	cases := []struct {
+		name        string
+		interesting imgvips.Interesting
+		left        int
+	}{
+		{"entropy", imgvips.InterestingEntropy, -1},
+		{"low", imgvips.InterestingLow, 0},
+		{"high", imgvips.InterestingHigh, 80},
+		{"centre", imgvips.InterestingCentre, 40},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out, area, err := imgvips.SmartCrop(img, 20, 30, c.interesting)
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			defer out.Free()
+
+			result, ok := out.Image()
+			if !ok || result == nil {
+				t.Fatal("Expected *C.VipsImage in out")
+			}
+			if result.Width() != 20 || result.Height() != 20 {
+				t.Errorf("Expected size %dx%d, got %dx%d", 20, 20, result.Width(), result.Height())
+			}
+			if c.left < 0 {
+				if area.Left >= 80 || area.Left+area.Width <= 60 || area.Width != 20 || area.Height != 20 {
+					t.Errorf("Expected 20x20 area overlapping [60, 80), got %+v", area)
+				}
+
+				return
+			}
+			expected := imgvips.CropArea{Left: c.left, Top: 0, Width: 20, Height: 20}
+			if area != expected {
+				t.Errorf("Expected area %+v, got %+v", expected, area)
+			}
+		})
+	}
+
+	if _, _, err := imgvips.SmartCrop(img, 0, 10, imgvips.InterestingCentre); err != imgvips.ErrInvalidGeometry {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrInvalidGeometry, err)
+	}
+}
+
+// noisyBandImage return 100x20 image, which is black except noisy band at x in [60, 80)
+func noisyBandImage(t *testing.T) *imgvips.GValue {
+	width, height := 100, 20
+	data := make([]byte, width*height)
+	for y := 0; y < height; y++ {
+		for x := 60; x < 80; x++ {
+			if (x+y)%2 == 0 {
+				data[y*width+x] = 255
+			}
+		}
+	}
+
+	val, err := imgvips.NewImageFromMemory(data, width, height, 1, imgvips.BandFormatUchar)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	return val
+}
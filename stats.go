@@ -0,0 +1,105 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "vips/vips.h"
+*/
+import "C"
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	liveOperations int64
+	liveValues     int64
+)
+
+var memHighwater = &highwater{}
+
+// highwater tracks memory high-water since last reset.
+// libvips high-water can't be reset, so if it did not grow since reset,
+// maximum of memory samples taken after every operation build is used.
+type highwater struct {
+	libvipsAtReset int64
+	sampled        int64
+	mu             sync.Mutex
+}
+
+func (h *highwater) sample() {
+	mem := int64(C.vips_tracked_get_mem())
+
+	h.mu.Lock()
+	if mem > h.sampled {
+		h.sampled = mem
+	}
+	h.mu.Unlock()
+}
+
+func (h *highwater) reset() {
+	h.mu.Lock()
+	h.libvipsAtReset = int64(C.vips_tracked_get_mem_highwater())
+	h.sampled = int64(C.vips_tracked_get_mem())
+	h.mu.Unlock()
+}
+
+func (h *highwater) get() int64 {
+	h.sample()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if libvips := int64(C.vips_tracked_get_mem_highwater()); libvips > h.libvipsAtReset {
+		return libvips
+	}
+
+	return h.sampled
+}
+
+// Stats contains snapshot of libvips resources usage
+type Stats struct {
+	// Mem is libvips tracked memory in bytes, it is actual usage including cached operations
+	Mem int64
+	// MemHighwater is libvips tracked memory high-water since start or last ResetHighwater()
+	MemHighwater int64
+	// Allocs is number of libvips tracked active allocations
+	Allocs int
+	// Files is number of libvips tracked open files
+	Files int
+	// CacheSize is number of operations in cache
+	CacheSize int
+	// CacheMax is maximum number of operations in cache
+	CacheMax int
+	// CacheMemLimit is limit of tracked memory, after which cache is trimmed. It is not memory usage, see Mem
+	CacheMemLimit int64
+	// CacheMaxFiles is maximum number of tracked files, after which cache is trimmed
+	CacheMaxFiles int
+	// Operations is number of operations created by NewOperation and not freed yet
+	Operations int64
+	// Values is number of GValues created and not freed yet
+	Values int64
+}
+
+// GetStats return snapshot of libvips resources usage
+func GetStats() Stats {
+	return Stats{
+		Mem:           int64(C.vips_tracked_get_mem()),
+		MemHighwater:  memHighwater.get(),
+		Allocs:        int(C.vips_tracked_get_allocs()),
+		Files:         int(C.vips_tracked_get_files()),
+		CacheSize:     int(C.vips_cache_get_size()),
+		CacheMax:      int(C.vips_cache_get_max()),
+		CacheMemLimit: int64(C.vips_cache_get_max_mem()),
+		CacheMaxFiles: int(C.vips_cache_get_max_files()),
+		Operations:    atomic.LoadInt64(&liveOperations),
+		Values:        atomic.LoadInt64(&liveValues),
+	}
+}
+
+// ResetHighwater resets Stats.MemHighwater to current tracked memory.
+//
+// GetMemHighwater() is not affected, it returns libvips high-water since start.
+func ResetHighwater() {
+	memHighwater.reset()
+}
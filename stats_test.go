@@ -0,0 +1,59 @@
+package imgvips_test
+
+import (
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestGetStats(t *testing.T) {
+	initVips(t)
+
+	before := imgvips.GetStats()
+
+	val, op := generateImage(t)
+
+	stats := imgvips.GetStats()
+	if stats.Operations != before.Operations+1 {
+		t.Errorf("Expected %d operations, got %d", before.Operations+1, stats.Operations)
+	}
+	if stats.Values != before.Values+1 {
+		t.Errorf("Expected %d values, got %d", before.Values+1, stats.Values)
+	}
+
+	val.Free()
+	op.Free()
+
+	after := imgvips.GetStats()
+	if after.Operations != before.Operations {
+		t.Errorf("Expected %d operations, got %d", before.Operations, after.Operations)
+	}
+	if after.Values != before.Values {
+		t.Errorf("Expected %d values, got %d", before.Values, after.Values)
+	}
+}
+
+func TestResetHighwater(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	initVips(t)
+
+	_, op := webpLoadBytes(t)
+	op.Free()
+
+	if imgvips.GetStats().MemHighwater <= 0 {
+		t.Error("Expected memory high-water bigger than 0")
+	}
+
+	imgvips.ResetHighwater()
+
+	stats := imgvips.GetStats()
+	if stats.MemHighwater != stats.Mem {
+		t.Errorf("Expected memory high-water %d, got %d", stats.Mem, stats.MemHighwater)
+	}
+	if imgvips.GetMemHighwater() <= 0 {
+		t.Error("Expected libvips memory high-water bigger than 0")
+	}
+}
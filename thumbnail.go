@@ -0,0 +1,357 @@
+package imgvips
+
+import (
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+)
+
+// Size is thumbnail resize mode, see VipsSize.
+// Values are set without C constants, because enum was added in libvips 8.6.
+type Size int
+
+// Available size modes
+const (
+	// SizeBoth up or down
+	SizeBoth Size = iota
+	// SizeUp only upsize
+	SizeUp
+	// SizeDown only downsize
+	SizeDown
+	// SizeForce change aspect ratio
+	SizeForce
+)
+
+// Interesting is strategy to find interesting region of image, see VipsInteresting.
+// Values are set without C constants, because enum was added in libvips 8.5.
+type Interesting int
+
+// Available strategies
+const (
+	// InterestingNone do nothing
+	InterestingNone Interesting = iota
+	// InterestingCentre just take the centre
+	InterestingCentre
+	// InterestingEntropy use an entropy measure
+	InterestingEntropy
+	// InterestingAttention look for features likely to draw human attention.
+	// SmartCrop() without libvips smartcrop operation uses InterestingEntropy instead.
+	InterestingAttention
+	// InterestingLow position the crop towards the low coordinate
+	InterestingLow
+	// InterestingHigh position the crop towards the high coordinate
+	InterestingHigh
+	// InterestingAll everything is interesting
+	InterestingAll
+)
+
+// ThumbnailOptions are optional arguments of Thumbnail()
+type ThumbnailOptions struct {
+	// Height is target height, if 0, it is equal to width, like in vips_thumbnail
+	Height int
+	// Size is resize mode
+	Size Size
+	// Crop is strategy to crop image to exactly width*height, InterestingNone turns crop off
+	Crop Interesting
+	// Linear reduces in linear light, it is ignored by fallback for libvips without thumbnail operations
+	Linear bool
+}
+
+// Thumbnail makes thumbnail of source with target width, using shrink-on-load when possible.
+//
+// Source can be []byte, string (file name), io.Reader or *GValue with image, which stays owned by caller.
+// Bytes array must be protected from GC and modification and io.Reader must stay readable while result is used.
+// If opts is nil, aspect ratio is kept and image is not cropped.
+//
+// If libvips has no thumbnail operations, image is loaded with jpeg shrink or webp scale computed from header,
+// then resized and cropped.
+// Thumbnail operations load source internally, so source header is loaded with detected loader first
+// to check loaders policy and Limits.
+//
+// Returned value is owned by caller, call *GValue.Free() after image is no longer needed.
+func Thumbnail(source interface{}, width int, opts *ThumbnailOptions) (*GValue, error) {
+	opts = thumbnailOptions(width, opts)
+
+	opName, argName, arg, err := thumbnailSource(source)
+	if err != nil {
+		return nil, err
+	}
+	if opName == "" {
+		return thumbnailFallback(source, width, opts)
+	}
+
+	if err := thumbnailProbe(source, arg); err != nil {
+		arg.Free()
+
+		return nil, err
+	}
+
+	op, err := NewOperation(opName)
+	if err != nil {
+		arg.Free()
+
+		return nil, err
+	}
+	defer op.Free()
+
+	op.AddInput(argName, arg)
+	op.AddInput("width", GInt(width))
+	// Optional arguments are set only if differ from defaults, so older libvips is supported
+	if opts.Height != width {
+		op.AddInput("height", GInt(opts.Height))
+	}
+	if opts.Size != SizeBoth {
+		op.AddInput("size", GInt(int(opts.Size)))
+	}
+	if opts.Crop != InterestingNone {
+		op.AddInput("crop", GInt(int(opts.Crop)))
+	}
+	if opts.Linear {
+		op.AddInput("linear", GBoolean(true))
+	}
+
+	out := GNullVipsImage()
+	op.AddOutput("out", out)
+
+	if err := op.Exec(); err != nil {
+		return nil, err
+	}
+
+	return out.ref(), nil
+}
+
+// thumbnailOptions return copy of opts with defaults set, height is equal to width by default, like in vips_thumbnail
+func thumbnailOptions(width int, opts *ThumbnailOptions) *ThumbnailOptions {
+	thumbnailOpts := ThumbnailOptions{}
+	if opts != nil {
+		thumbnailOpts = *opts
+	}
+	if thumbnailOpts.Height <= 0 {
+		thumbnailOpts.Height = width
+	}
+
+	return &thumbnailOpts
+}
+
+// thumbnailSource return thumbnail operation and its input for source, or empty name if operation is not supported
+func thumbnailSource(source interface{}) (opName, argName string, arg Value, err error) {
+	switch src := source.(type) {
+	case []byte:
+		if HasOperation("thumbnail_buffer") {
+			return "thumbnail_buffer", "buffer", GVipsBlob(src), nil
+		}
+	case string:
+		if HasOperation("thumbnail") {
+			return "thumbnail", "filename", GString(src), nil
+		}
+	case *GValue:
+		if !src.isImage() {
+			return "", "", nil, ErrUnsupportedArgument
+		}
+		if src.wasFreed() {
+			return "", "", nil, ErrImageAlreadyFreed
+		}
+		if HasOperation("thumbnail_image") {
+			return "thumbnail_image", "in", src.ref(), nil
+		}
+	case io.Reader:
+		if HasOperation("thumbnail_source") {
+			val, err := GVipsSourceFromReader(src)
+			if err != nil {
+				return "", "", nil, err
+			}
+
+			return "thumbnail_source", "source", val, nil
+		}
+	default:
+		return "", "", nil, ErrUnsupportedArgument
+	}
+
+	return "", "", nil, nil
+}
+
+// thumbnailProbe loads source header with loader detected by libvips,
+// so loaders policy and Limits are checked before thumbnail operation loads source internally
+func thumbnailProbe(source interface{}, arg Value) error {
+	var op *Operation
+	var err error
+
+	switch src := source.(type) {
+	case []byte:
+		_, op, err = LoadBuffer(src)
+	case string:
+		_, op, err = LoadFile(src)
+	case io.Reader:
+		val := arg.(*GValue)
+
+		var opName string
+		if opName, err = val.findLoadSource(); err != nil {
+			return err
+		}
+		// Loader reads only header, thumbnail operation rewinds source and loads it again
+		_, op, err = load(opName, "source", val.sourceRef())
+	default:
+		// Image is already loaded
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	op.Free()
+
+	return nil
+}
+
+func thumbnailFallback(source interface{}, width int, opts *ThumbnailOptions) (*GValue, error) {
+	in, op, err := thumbnailLoad(source, width, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer freeOperation(op)
+	defer in.Free()
+
+	img, _ := in.Image()
+	hscale, vscale := thumbnailScale(img.Width(), img.Height(), width, opts)
+
+	var resized *GValue
+	if hscale != 1 || vscale != 1 {
+		if resized, err = img.Resize(hscale, &ResizeOptions{VScale: vscale}); err != nil {
+			return nil, err
+		}
+	} else {
+		resized = in.ref()
+	}
+
+	if opts.Crop == InterestingNone {
+		return resized, nil
+	}
+	defer resized.Free()
+
+	resizedImg, _ := resized.Image()
+	cropWidth := minInt(width, resizedImg.Width())
+	cropHeight := minInt(opts.Height, resizedImg.Height())
+
+	if opts.Crop != InterestingCentre && HasOperation("smartcrop") {
+		return resizedImg.transform("smartcrop", Args{"width": cropWidth, "height": cropHeight, "interesting": int(opts.Crop)})
+	}
+
+	return resizedImg.Crop((resizedImg.Width()-cropWidth)/2, (resizedImg.Height()-cropHeight)/2, cropWidth, cropHeight)
+}
+
+// thumbnailLoad loads image, reloading it with jpeg shrink or webp scale, if thumbnail is much smaller.
+// Returned operation owns image and is nil, if source is image.
+func thumbnailLoad(source interface{}, width int, opts *ThumbnailOptions) (*GValue, *Operation, error) {
+	var argName string
+	var newArg func() *GValue
+	var in *GValue
+	var op *Operation
+	var err error
+
+	switch src := source.(type) {
+	case *GValue:
+		in, err = src.Copy()
+
+		return in, nil, err
+	case []byte:
+		argName, newArg = "buffer", func() *GValue { return GVipsBlob(src) }
+		in, op, err = LoadBuffer(src)
+	case string:
+		argName, newArg = "filename", func() *GValue { return GString(src) }
+		in, op, err = LoadFile(src)
+	case io.Reader:
+		var data []byte
+		if data, err = ioutil.ReadAll(src); err != nil {
+			return nil, nil, err
+		}
+		// Data must outlive returned image, so every load gets own copy in C memory
+		argName, newArg = "buffer", func() *GValue { return GVipsBlobCopy(data) }
+
+		var opName string
+		if opName, err = findLoadBuffer(data); err != nil {
+			return nil, nil, err
+		}
+		in, op, err = load(opName, argName, newArg())
+	default:
+		return nil, nil, ErrUnsupportedArgument
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	img, _ := in.Image()
+	hscale, vscale := thumbnailScale(img.Width(), img.Height(), width, opts)
+	factor := 1 / math.Max(hscale, vscale)
+	loader := op.nickname()
+
+	var shrinkName string
+	var shrinkValue *GValue
+	switch {
+	case factor < 2:
+	case strings.HasPrefix(loader, "jpegload"):
+		shrink := 8
+		for float64(shrink) > factor {
+			shrink /= 2
+		}
+		shrinkName, shrinkValue = "shrink", GInt(shrink)
+	case strings.HasPrefix(loader, "webpload") && HasArgument(loader, "scale"):
+		shrinkName, shrinkValue = "scale", GDouble(1/factor)
+	case strings.HasPrefix(loader, "webpload") && HasArgument(loader, "shrink"):
+		shrinkName, shrinkValue = "shrink", GInt(int(factor))
+	}
+	if shrinkValue == nil {
+		return in, op, nil
+	}
+
+	shrunkOp, err := NewOperation(loader)
+	if err != nil {
+		shrinkValue.Free()
+
+		return in, op, nil
+	}
+
+	shrunk := GNullVipsImage()
+	shrunkOp.AddInput(argName, newArg())
+	shrunkOp.AddInput(shrinkName, shrinkValue)
+	shrunkOp.AddOutput("out", shrunk)
+
+	if err := shrunkOp.Exec(); err != nil {
+		shrunkOp.Free()
+		op.Free()
+
+		return nil, nil, err
+	}
+	op.Free()
+
+	return shrunk, shrunkOp, nil
+}
+
+// thumbnailScale return horizontal and vertical scale to fit or fill width*height box.
+// Options must be prepared by thumbnailOptions().
+func thumbnailScale(imgWidth, imgHeight, width int, opts *ThumbnailOptions) (hscale, vscale float64) {
+	hscale = float64(width) / float64(imgWidth)
+	vscale = float64(opts.Height) / float64(imgHeight)
+
+	if opts.Size == SizeForce {
+		return hscale, vscale
+	}
+
+	scale := math.Min(hscale, vscale)
+	if opts.Crop != InterestingNone {
+		scale = math.Max(hscale, vscale)
+	}
+
+	if (opts.Size == SizeDown && scale > 1) || (opts.Size == SizeUp && scale < 1) {
+		scale = 1
+	}
+
+	return scale, scale
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,52 @@
+package imgvips
+
+import (
+	"testing"
+)
+
+func initVipsInternal(t testing.TB) {
+	if err := Initialize(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+// Fallback is used only with libvips without thumbnail operations, so it is called directly
+func TestThumbnailFallback(t *testing.T) {
+	initVipsInternal(t)
+
+	portrait, err := NewImageFromMemory(make([]byte, 100*200), 100, 200, 1, BandFormatUchar)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer portrait.Free()
+
+	cases := []struct {
+		name           string
+		opts           *ThumbnailOptions
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{"fit", nil, 25, 50},
+		{"crop", &ThumbnailOptions{Crop: InterestingCentre}, 50, 50},
+		{"force", &ThumbnailOptions{Size: SizeForce}, 50, 50},
+		{"height", &ThumbnailOptions{Height: 40}, 20, 40},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out, err := thumbnailFallback(portrait, 50, thumbnailOptions(50, c.opts))
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			defer out.Free()
+
+			img, ok := out.Image()
+			if !ok || img == nil {
+				t.Fatal("Expected *C.VipsImage in out")
+			}
+			if img.Width() != c.expectedWidth || img.Height() != c.expectedHeight {
+				t.Errorf("Expected size %dx%d, got %dx%d", c.expectedWidth, c.expectedHeight, img.Width(), img.Height())
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package imgvips_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestThumbnail(t *testing.T) {
+	initVips(t)
+
+	filename := "./tests/fixtures/img.webp"
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	in, op := generateImage(t)
+	defer op.Free()
+
+	portrait, err := imgvips.NewImageFromMemory(make([]byte, 100*200), 100, 200, 1, imgvips.BandFormatUchar)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer portrait.Free()
+
+	cases := []struct {
+		name           string
+		source         interface{}
+		width          int
+		opts           *imgvips.ThumbnailOptions
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{"bytes", data, 100, nil, 100, 100},
+		{"file", filename, 100, nil, 100, 100},
+		{"reader", bytes.NewReader(data), 100, nil, 100, 100},
+		{"image", in, 50, nil, 50, 50},
+		{"crop", data, 100, &imgvips.ThumbnailOptions{Height: 50, Crop: imgvips.InterestingAttention}, 100, 50},
+		{"no crop", data, 100, &imgvips.ThumbnailOptions{Height: 50}, 50, 50},
+		{"force", data, 100, &imgvips.ThumbnailOptions{Height: 50, Size: imgvips.SizeForce}, 100, 50},
+		{"down", in, 200, &imgvips.ThumbnailOptions{Size: imgvips.SizeDown}, 100, 100},
+		{"linear", in, 50, &imgvips.ThumbnailOptions{Linear: true}, 50, 50},
+		{"portrait", portrait, 50, nil, 25, 50},
+		{"portrait crop", portrait, 50, &imgvips.ThumbnailOptions{Crop: imgvips.InterestingCentre}, 50, 50},
+		{"portrait force", portrait, 50, &imgvips.ThumbnailOptions{Size: imgvips.SizeForce}, 50, 50},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out, err := imgvips.Thumbnail(c.source, c.width, c.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			defer out.Free()
+
+			img, ok := out.Image()
+			if !ok || img == nil {
+				t.Fatal("Expected *C.VipsImage in out")
+			}
+			if img.Width() != c.expectedWidth || img.Height() != c.expectedHeight {
+				t.Errorf("Expected size %dx%d, got %dx%d", c.expectedWidth, c.expectedHeight, img.Width(), img.Height())
+			}
+			if _, err := img.WriteToMemory(); err != nil {
+				t.Errorf("Unexpected error %v", err)
+			}
+		})
+	}
+
+	if src, ok := in.Image(); !ok || src == nil || src.Width() != 100 {
+		t.Error("Expected source image stays owned by caller")
+	}
+
+	if _, err := imgvips.Thumbnail(42, 100, nil); err != imgvips.ErrUnsupportedArgument {
+		t.Errorf("Expected error %v, got %v", imgvips.ErrUnsupportedArgument, err)
+	}
+}
+
+func TestThumbnail_Limits(t *testing.T) {
+	initVips(t)
+
+	imgvips.SetLimits(imgvips.Limits{MaxWidth: 50})
+	defer imgvips.SetLimits(imgvips.Limits{})
+
+	// Thumbnail is smaller than limits, but source is checked before load
+	if _, err := imgvips.Thumbnail("./tests/fixtures/img.webp", 10, nil); !errors.Is(err, imgvips.ErrLimitExceeded) {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrLimitExceeded, err)
+	}
+}
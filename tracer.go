@@ -0,0 +1,38 @@
+package imgvips
+
+import (
+	"sync"
+	"time"
+)
+
+// Tracer receives events of *Operation.Exec(), e.g. for spans or histograms export.
+//
+// Methods are called from goroutine, which executes operation, so they must be safe for concurrent use.
+type Tracer interface {
+	// OnStart is called before operation execution with operation nickname and names of input arguments
+	OnStart(opName string, args []string)
+	// OnFinish is called after operation execution with its duration, libvips tracked memory delta and result.
+	// Tracked memory is global, so delta includes memory of concurrently executed operations.
+	OnFinish(opName string, duration time.Duration, memDelta int64, err error)
+}
+
+var tracerHolder = &tracerState{}
+
+type tracerState struct {
+	tracer Tracer
+	mu     sync.RWMutex
+}
+
+// SetTracer set tracer for all operations. Pass nil to turn tracing off.
+func SetTracer(tracer Tracer) {
+	tracerHolder.mu.Lock()
+	tracerHolder.tracer = tracer
+	tracerHolder.mu.Unlock()
+}
+
+func getTracer() Tracer {
+	tracerHolder.mu.RLock()
+	defer tracerHolder.mu.RUnlock()
+
+	return tracerHolder.tracer
+}
@@ -0,0 +1,80 @@
+package imgvips_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Arimeka/imgvips"
+)
+
+type traceEvent struct {
+	opName   string
+	args     []string
+	duration time.Duration
+	err      error
+}
+
+type testTracer struct {
+	started  []traceEvent
+	finished []traceEvent
+	mu       sync.Mutex
+}
+
+func (tr *testTracer) OnStart(opName string, args []string) {
+	tr.mu.Lock()
+	tr.started = append(tr.started, traceEvent{opName: opName, args: args})
+	tr.mu.Unlock()
+}
+
+func (tr *testTracer) OnFinish(opName string, duration time.Duration, memDelta int64, err error) {
+	tr.mu.Lock()
+	tr.finished = append(tr.finished, traceEvent{opName: opName, duration: duration, err: err})
+	tr.mu.Unlock()
+}
+
+func TestSetTracer(t *testing.T) {
+	initVips(t)
+
+	tracer := &testTracer{}
+	imgvips.SetTracer(tracer)
+	defer imgvips.SetTracer(nil)
+
+	_, op := generateImage(t)
+	defer op.Free()
+
+	failOp, err := imgvips.NewOperation("resize")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer failOp.Free()
+
+	failErr := failOp.Exec()
+	if failErr == nil {
+		t.Fatal("Expected to return error, got nil")
+	}
+
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	if len(tracer.started) != 2 || len(tracer.finished) != 2 {
+		t.Fatalf("Expected %d events, got %d started and %d finished", 2, len(tracer.started), len(tracer.finished))
+	}
+
+	if tracer.started[0].opName != "grey" {
+		t.Errorf("Expected operation %s, got %s", "grey", tracer.started[0].opName)
+	}
+	if len(tracer.started[0].args) != 2 || tracer.started[0].args[0] != "width" || tracer.started[0].args[1] != "height" {
+		t.Errorf("Expected args %v, got %v", []string{"width", "height"}, tracer.started[0].args)
+	}
+	if tracer.finished[0].err != nil {
+		t.Errorf("Unexpected error %v", tracer.finished[0].err)
+	}
+	if tracer.finished[0].duration <= 0 {
+		t.Errorf("Expected positive duration, got %v", tracer.finished[0].duration)
+	}
+
+	if tracer.finished[1].opName != "resize" || tracer.finished[1].err != failErr {
+		t.Errorf("Expected resize with error %v, got %s with error %v", failErr, tracer.finished[1].opName, tracer.finished[1].err)
+	}
+}
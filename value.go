@@ -9,7 +9,9 @@ import "C"
 
 import (
 	"errors"
+	"reflect"
 	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -57,13 +59,35 @@ func (v *GValue) Free() {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
+	if v.gValue != nil {
+		atomic.AddInt64(&liveValues, -1)
+	}
+
 	v.free(v)
 	v.gValue = nil
 }
 
+// init initialize gValue with gType
+func (v *GValue) init() {
+	C.g_value_init(v.gValue, v.gType)
+	atomic.AddInt64(&liveValues, 1)
+}
+
 func (v *GValue) wasFreed() bool {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 
 	return v.gValue == nil
 }
+
+// cBytes return bytes slice backed by C memory without copy
+func cBytes(ptr unsafe.Pointer, size int) []byte {
+	var data []byte
+
+	header := (*reflect.SliceHeader)(unsafe.Pointer(&data))
+	header.Data = uintptr(ptr)
+	header.Len = size
+	header.Cap = size
+
+	return data
+}
@@ -0,0 +1,55 @@
+package imgvips
+
+/*
+#cgo pkg-config: vips
+#include "stdlib.h"
+#include "vips/vips.h"
+
+static gboolean imgvips_has_argument(const char *operation_name, const char *name) {
+	VipsOperation *op = vips_operation_new(operation_name);
+	if (op == NULL) {
+		vips_error_clear();
+
+		return FALSE;
+	}
+
+	gboolean found = g_object_class_find_property(G_OBJECT_GET_CLASS(op), name) != NULL;
+	g_object_unref(op);
+
+	return found;
+}
+
+static gboolean imgvips_has_operation(const char *name) {
+	GType type = vips_type_find("VipsOperation", name);
+
+	return type != 0 && !G_TYPE_IS_ABSTRACT(type);
+}
+*/
+import "C"
+
+// Version return version of linked libvips
+func Version() (major, minor, micro int) {
+	return int(C.vips_version(0)), int(C.vips_version(1)), int(C.vips_version(2))
+}
+
+// VersionAtLeast return true if linked libvips version is equal or higher than major.minor
+func VersionAtLeast(major, minor int) bool {
+	vMajor, vMinor, _ := Version()
+
+	return vMajor > major || (vMajor == major && vMinor >= minor)
+}
+
+// HasOperation return true if libvips known operation with provided name.
+// Abstract operations, e.g. foreign or conversion, can't be created, so false is returned for them.
+func HasOperation(name string) bool {
+	return C.imgvips_has_operation(cStringsCache.get(name)) != 0
+}
+
+// HasArgument return true if operation has argument with provided name
+func HasArgument(operation, name string) bool {
+	if !HasOperation(operation) {
+		return false
+	}
+
+	return C.imgvips_has_argument(cStringsCache.get(operation), cStringsCache.get(name)) != 0
+}
@@ -0,0 +1,54 @@
+package imgvips_test
+
+import (
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+)
+
+func TestVersion(t *testing.T) {
+	initVips(t)
+
+	major, minor, micro := imgvips.Version()
+	if major < 8 {
+		t.Fatalf("Expected libvips 8+, got %d.%d.%d", major, minor, micro)
+	}
+
+	if !imgvips.VersionAtLeast(major, minor) {
+		t.Errorf("Expected version to be at least %d.%d", major, minor)
+	}
+	if !imgvips.VersionAtLeast(major-1, minor+1) {
+		t.Errorf("Expected version to be at least %d.%d", major-1, minor+1)
+	}
+	if imgvips.VersionAtLeast(major, minor+1) {
+		t.Errorf("Expected version to be lower than %d.%d", major, minor+1)
+	}
+}
+
+func TestHasOperation(t *testing.T) {
+	initVips(t)
+
+	if !imgvips.HasOperation("resize") {
+		t.Error("Expected to have resize operation")
+	}
+	if imgvips.HasOperation("non_exists") {
+		t.Error("Expected to not have non_exists operation")
+	}
+	if imgvips.HasOperation("foreign") {
+		t.Error("Expected to not have abstract foreign operation")
+	}
+}
+
+func TestHasArgument(t *testing.T) {
+	initVips(t)
+
+	if !imgvips.HasArgument("resize", "scale") {
+		t.Error("Expected resize to have scale argument")
+	}
+	if imgvips.HasArgument("resize", "non_exists") {
+		t.Error("Expected resize to not have non_exists argument")
+	}
+	if imgvips.HasArgument("non_exists", "scale") {
+		t.Error("Expected non_exists to not have scale argument")
+	}
+}
@@ -10,17 +10,88 @@ import "C"
 import (
 	"errors"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
 var (
 	errVipsFailedStart = errors.New("unable to start vips")
+
+	// ErrNotInitialized returns when libvips was shut down
+	ErrNotInitialized = errors.New("vips not initialized")
+	// ErrAlreadyShutdown returns when Initialize called after Shutdown
+	ErrAlreadyShutdown = errors.New("vips already shut down")
+
+	// ErrNotSupported returns when feature is not supported by linked libvips version
+	ErrNotSupported = errors.New("not supported by libvips version")
 )
 
+var vipsState = &state{}
+
+type state struct {
+	initialized bool
+	shutdown    bool
+	mu          sync.RWMutex
+}
+
+func (s *state) isShutdown() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.shutdown
+}
+
 // Initialize libvips
 //
 // By default, libvips cache will be turned off (set to zero), vector arithmetic - turned on.
+//
+// It is safe to call Initialize many times: libvips is started only once.
+// Defaults are applied only on first call, later calls apply only passed options,
+// so it can be used for reconfiguration without reset of values set by SetCacheMax() and others.
+// Initialize can't be called after Shutdown, it will return ErrAlreadyShutdown.
 func Initialize(options ...InitOption) error {
+	vipsState.mu.Lock()
+	defer vipsState.mu.Unlock()
+
+	if vipsState.shutdown {
+		return ErrAlreadyShutdown
+	}
+
+	opts := initOptions{}
+
+	for _, option := range options {
+		option.f(&opts)
+	}
+
+	if !vipsState.initialized {
+		if err := vipsInit(); err != nil {
+			return err
+		}
+		vipsState.initialized = true
+
+		opts.set = optionsAll
+	}
+
+	if opts.set&optionDetectMemoryLeak != 0 {
+		vipsDetectMemoryLeak(opts.detectMemoryLeak)
+	}
+	if opts.set&optionEnableVector != 0 {
+		vipsVectorSetEnables(opts.enableVector)
+	}
+	if opts.set&optionCacheMax != 0 {
+		vipsCacheSetMax(opts.cacheMax)
+	}
+	if opts.set&optionCacheMaxMem != 0 {
+		vipsCacheSetMaxMem(opts.cacheMaxMem)
+	}
+	if opts.set&optionConcurrency != 0 {
+		vipsConcurrencySet(opts.concurrency)
+	}
+
+	return nil
+}
+
+func vipsInit() error {
 	// Lock OS thread to current goroutine while initializing libvips
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
@@ -32,19 +103,28 @@ func Initialize(options ...InitOption) error {
 		return errVipsFailedStart
 	}
 
-	opts := initOptions{}
+	logSetHandler()
 
-	for _, option := range options {
-		option.f(&opts)
+	return nil
+}
+
+// Shutdown drops libvips cache, stops libvips background threads and frees its resources.
+//
+// If memory leak reports are turned on with VipsDetectMemoryLeak, libvips prints report to stderr.
+// After Shutdown, NewOperation returns ErrNotInitialized and libvips can't be initialized again.
+// All values and operations must be freed before call.
+func Shutdown() {
+	vipsState.mu.Lock()
+	defer vipsState.mu.Unlock()
+
+	if vipsState.shutdown {
+		return
 	}
 
-	vipsDetectMemoryLeak(opts.detectMemoryLeak)
-	vipsVectorSetEnables(opts.enableVector)
-	vipsCacheSetMax(opts.cacheMax)
-	vipsCacheSetMaxMem(opts.cacheMaxMem)
-	vipsConcurrencySet(opts.concurrency)
+	C.vips_cache_drop_all()
+	C.vips_shutdown()
 
-	return nil
+	vipsState.shutdown = true
 }
 
 // InitOption specifies an option for initialize libvips
@@ -58,12 +138,27 @@ type initOptions struct {
 	cacheMax         int
 	cacheMaxMem      int
 	concurrency      int
+	// set marks options, which were passed to Initialize
+	set initOption
 }
 
+type initOption uint
+
+const (
+	optionDetectMemoryLeak initOption = 1 << iota
+	optionEnableVector
+	optionCacheMax
+	optionCacheMaxMem
+	optionConcurrency
+
+	optionsAll = optionDetectMemoryLeak | optionEnableVector | optionCacheMax | optionCacheMaxMem | optionConcurrency
+)
+
 // VipsDetectMemoryLeak turn on/off memory leak reports
 func VipsDetectMemoryLeak(on bool) InitOption {
 	return InitOption{func(options *initOptions) {
-		options.detectMemoryLeak = true
+		options.detectMemoryLeak = on
+		options.set |= optionDetectMemoryLeak
 	}}
 }
 
@@ -80,6 +175,7 @@ func vipsDetectMemoryLeak(on bool) {
 func VipsCacheSetMax(n int) InitOption {
 	return InitOption{func(options *initOptions) {
 		options.cacheMax = n
+		options.set |= optionCacheMax
 	}}
 }
 
@@ -87,13 +183,17 @@ func vipsCacheSetMax(n int) {
 	if n < 0 {
 		n = 0
 	}
+
+	cacheMaxMu.Lock()
 	C.vips_cache_set_max(C.int(n))
+	cacheMaxMu.Unlock()
 }
 
 // VipsCacheSetMaxMem set maximum amount of tracked memory
 func VipsCacheSetMaxMem(n int) InitOption {
 	return InitOption{func(options *initOptions) {
 		options.cacheMaxMem = n
+		options.set |= optionCacheMaxMem
 	}}
 }
 
@@ -108,6 +208,7 @@ func vipsCacheSetMaxMem(n int) {
 func VipsVectorSetEnables(enabled bool) InitOption {
 	return InitOption{func(options *initOptions) {
 		options.enableVector = enabled
+		options.set |= optionEnableVector
 	}}
 }
 
@@ -124,6 +225,7 @@ func vipsVectorSetEnables(enabled bool) {
 func VipsConcurrencySet(n int) InitOption {
 	return InitOption{func(options *initOptions) {
 		options.concurrency = n
+		options.set |= optionConcurrency
 	}}
 }
 
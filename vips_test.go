@@ -1,6 +1,8 @@
 package imgvips_test
 
 import (
+	"os"
+	"os/exec"
 	"testing"
 
 	"github.com/Arimeka/imgvips"
@@ -15,6 +17,68 @@ func initVips(t testing.TB) {
 	}
 }
 
+func TestInitialize(t *testing.T) {
+	initVips(t)
+	initVips(t)
+
+	op, err := imgvips.NewOperation("jpegload")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	op.Free()
+}
+
+func TestInitialize_KeepRuntimeSettings(t *testing.T) {
+	initVips(t)
+	defer imgvips.SetCacheMax(0)
+
+	imgvips.SetCacheMax(10)
+
+	if err := imgvips.Initialize(); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if imgvips.CacheMax() != 10 {
+		t.Errorf("Expected cache max %d, got %d", 10, imgvips.CacheMax())
+	}
+
+	if err := imgvips.Initialize(imgvips.VipsCacheSetMax(20)); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if imgvips.CacheMax() != 20 {
+		t.Errorf("Expected cache max %d, got %d", 20, imgvips.CacheMax())
+	}
+}
+
+// Shutdown can't be reverted, so it is tested in separate process
+func TestShutdown(t *testing.T) {
+	if os.Getenv("IMGVIPS_TEST_SHUTDOWN") == "1" {
+		testShutdown(t)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestShutdown$") // nolint:gosec // For testing
+	cmd.Env = append(os.Environ(), "IMGVIPS_TEST_SHUTDOWN=1")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Unexpected error %v: %s", err, output)
+	}
+}
+
+func testShutdown(t *testing.T) {
+	initVips(t)
+
+	imgvips.Shutdown()
+	// Check multiply shutdown
+	imgvips.Shutdown()
+
+	if _, err := imgvips.NewOperation("jpegload"); err != imgvips.ErrNotInitialized {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrNotInitialized, err)
+	}
+
+	if err := imgvips.Initialize(); err != imgvips.ErrAlreadyShutdown {
+		t.Fatalf("Expected error %v, got %v", imgvips.ErrAlreadyShutdown, err)
+	}
+}
+
 func TestGetAllocs(t *testing.T) {
 	initVips(t)
 
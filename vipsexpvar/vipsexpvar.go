@@ -0,0 +1,22 @@
+/*
+Package vipsexpvar publishes imgvips.GetStats() through expvar, so libvips memory
+can be charted alongside Go runtime stats from /debug/vars.
+
+It lives in separate package, because importing expvar registers /debug/vars handler in http.DefaultServeMux.
+*/
+package vipsexpvar
+
+import (
+	"expvar"
+
+	"github.com/Arimeka/imgvips"
+)
+
+// Publish publishes imgvips stats as expvar variable with provided name.
+//
+// Stats are collected on every read. Like expvar.Publish, it panics if name is already registered.
+func Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return imgvips.GetStats()
+	}))
+}
@@ -0,0 +1,31 @@
+package vipsexpvar_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/Arimeka/imgvips"
+	"github.com/Arimeka/imgvips/vipsexpvar"
+)
+
+func TestPublish(t *testing.T) {
+	if err := imgvips.Initialize(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	vipsexpvar.Publish("imgvips")
+
+	v := expvar.Get("imgvips")
+	if v == nil {
+		t.Fatal("Expected published variable")
+	}
+
+	var stats imgvips.Stats
+	if err := json.Unmarshal([]byte(v.String()), &stats); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if stats.MemHighwater < stats.Mem {
+		t.Errorf("Expected high-water %d to be not lower than memory %d", stats.MemHighwater, stats.Mem)
+	}
+}